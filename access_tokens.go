@@ -0,0 +1,111 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/srinivassivaratri/Chirpy/internal/auth"
+	"github.com/srinivassivaratri/Chirpy/internal/database"
+)
+
+const (
+	// How long an access JWT is good for
+	accessTokenLifetime = time.Hour
+	// How far apart our clock and the client's can be, unless JWT_LEEWAY
+	// says otherwise
+	defaultJWTLeeway = 30 * time.Second
+	// How often denylist entries for tokens that have expired anyway are
+	// deleted
+	deniedTokensCleanupInterval = time.Hour
+)
+
+// newJWTConfig reads the issuer, audience and leeway access JWTs are made
+// and checked with. Changing the issuer or audience logs everyone's access
+// tokens out, though refresh tokens keep working.
+func newJWTConfig(keys *auth.Keyring, denylist auth.Denylist) (auth.JWTConfig, error) {
+	config := auth.JWTConfig{
+		Keys:     keys,
+		Issuer:   os.Getenv("JWT_ISSUER"),
+		Audience: os.Getenv("JWT_AUDIENCE"),
+		Leeway:   defaultJWTLeeway,
+		Denylist: denylist,
+	}
+	if leeway := os.Getenv("JWT_LEEWAY"); leeway != "" {
+		d, err := time.ParseDuration(leeway)
+		if err != nil || d < 0 {
+			return auth.JWTConfig{}, fmt.Errorf("invalid JWT_LEEWAY %q", leeway)
+		}
+		config.Leeway = d
+	}
+	return config, nil
+}
+
+// tokenDenylist is the denied_tokens table
+type tokenDenylist struct {
+	db *database.Queries
+}
+
+func (d tokenDenylist) Denied(ctx context.Context, tokenID string) (bool, error) {
+	return d.db.IsTokenDenied(ctx, tokenID)
+}
+
+// cleanupDeniedTokensLoop deletes denylist entries once the tokens they name
+// would have expired anyway
+func (cfg *apiConfig) cleanupDeniedTokensLoop(ctx context.Context) {
+	ticker := time.NewTicker(deniedTokensCleanupInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		_, err := cfg.db.DeleteExpiredDeniedTokens(ctx)
+		if err != nil {
+			log.Printf("Error deleting expired denied tokens: %s", err)
+		}
+	}
+}
+
+// respondWithAuthError turns down a request whose bearer token couldn't be
+// used, saying why in the WWW-Authenticate header as RFC 6750 describes
+func respondWithAuthError(w http.ResponseWriter, err error) {
+	challenge := `Bearer realm="chirpy"`
+	code := http.StatusUnauthorized
+	msg := "Couldn't validate JWT"
+	switch {
+	case errors.Is(err, auth.ErrNoAuthHeaderIncluded):
+		msg = "Couldn't find JWT"
+	case errors.Is(err, auth.ErrMalformedAuthHeader):
+		code = http.StatusBadRequest
+		msg = "Malformed authorization header"
+		challenge += `, error="invalid_request"`
+	case errors.Is(err, auth.ErrTokenExpired):
+		msg = "JWT has expired"
+	case errors.Is(err, auth.ErrTokenRevoked):
+		msg = "JWT has been revoked"
+	case errors.Is(err, auth.ErrTokenNotYetValid):
+		msg = "JWT isn't valid yet"
+	case errors.Is(err, auth.ErrBadSignature):
+		msg = "JWT signature is invalid"
+	case errors.Is(err, auth.ErrWrongIssuer), errors.Is(err, auth.ErrWrongAudience):
+		msg = "JWT wasn't issued for this service"
+	case errors.Is(err, auth.ErrMalformedToken):
+		msg = "JWT is malformed"
+	default:
+		// Not the token's fault, the database probably couldn't be reached
+		respondWithError(w, http.StatusInternalServerError, "Couldn't validate JWT", err)
+		return
+	}
+	if code == http.StatusUnauthorized && !errors.Is(err, auth.ErrNoAuthHeaderIncluded) {
+		challenge += fmt.Sprintf(`, error="invalid_token", error_description=%q`, msg)
+	}
+	w.Header().Set("WWW-Authenticate", challenge)
+	respondWithError(w, code, msg, err)
+}
@@ -0,0 +1,213 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/srinivassivaratri/Chirpy/internal/database"
+	"github.com/srinivassivaratri/Chirpy/internal/pubsub"
+	"github.com/srinivassivaratri/Chirpy/internal/stream"
+)
+
+const (
+	chirpEventCreated = "chirp_created"
+	chirpEventDeleted = "chirp_deleted"
+	chirpEventHidden  = "chirp_hidden"
+)
+
+// chirpEventsChannel is the pubsub channel that tells every instance there's
+// something new in the log
+const chirpEventsChannel = "chirp_events"
+
+const (
+	// How often each instance reads the log without being told to. Pubsub
+	// normally wakes it straight away; this catches anything it missed.
+	chirpEventPollInterval = 5 * time.Second
+	// Event IDs come from a sequence, so a transaction that took a lower ID
+	// can commit after a higher one has been read. Events younger than this
+	// are read again on the next poll in case one shows up behind them.
+	chirpEventSettle = 10 * time.Second
+	// How long clients can resume from an event
+	chirpEventRetention = 24 * time.Hour
+	// Most events read from the log at once
+	chirpEventBatchSize = 500
+)
+
+// chirpEventMessage is published on chirpEventsChannel once an event is in the log
+type chirpEventMessage struct {
+	Kind    string    `json:"kind"`
+	ChirpID uuid.UUID `json:"chirp_id"`
+}
+
+// publishChirpEvent wakes every instance's poller. The event is already
+// committed to the log, so a failure here only delays it until the next
+// poll.
+func (cfg *apiConfig) publishChirpEvent(ctx context.Context, kind string, chirpID uuid.UUID) {
+	payload, err := json.Marshal(chirpEventMessage{Kind: kind, ChirpID: chirpID})
+	if err != nil {
+		log.Printf("Error encoding chirp event: %s", err)
+		return
+	}
+	err = cfg.pubsub.Publish(ctx, chirpEventsChannel, string(payload))
+	if err != nil {
+		log.Printf("Error publishing chirp event: %s", err)
+	}
+}
+
+// pollChirpEventsLoop publishes events from the log to this instance's stream
+// subscribers whenever pubsub says there's something new. Every instance runs
+// one, which is how a chirp posted through one instance reaches clients
+// streaming from another.
+func (cfg *apiConfig) pollChirpEventsLoop(ctx context.Context) {
+	ticker := time.NewTicker(chirpEventPollInterval)
+	defer ticker.Stop()
+	pruneTicker := time.NewTicker(time.Hour)
+	defer pruneTicker.Stop()
+
+	// Without pubsub the loop still works, just a poll interval behind
+	var wakeups <-chan pubsub.Message
+	sub, err := cfg.pubsub.Subscribe(chirpEventsChannel)
+	if err != nil {
+		log.Printf("Error subscribing to chirp events: %s", err)
+	} else {
+		defer sub.Close()
+		wakeups = sub.Messages()
+	}
+
+	// Start at the end of the log. Older events only go to clients that ask
+	// to resume from them.
+	started := false
+	settledID := int64(0)
+	published := map[int64]bool{}
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-pruneTicker.C:
+			err := cfg.db.DeleteOldChirpEvents(ctx, chirpEventRetention.Seconds())
+			if err != nil {
+				log.Printf("Error pruning chirp events: %s", err)
+			}
+			continue
+		case <-wakeups:
+		case <-ticker.C:
+		}
+
+		if !started {
+			latestID, err := cfg.db.GetLatestChirpEventID(ctx)
+			if err != nil {
+				log.Printf("Error reading chirp events: %s", err)
+				continue
+			}
+			settledID = latestID
+			started = true
+		}
+		err := cfg.pollChirpEvents(ctx, &settledID, published)
+		if err != nil {
+			log.Printf("Error reading chirp events: %s", err)
+		}
+	}
+}
+
+// pollChirpEvents publishes the events after settledID that haven't been
+// published yet, then moves settledID past the ones that have settled
+func (cfg *apiConfig) pollChirpEvents(ctx context.Context, settledID *int64, published map[int64]bool) error {
+	dbEvents, err := cfg.db.ListChirpEventsAfter(ctx, database.ListChirpEventsAfterParams{
+		SettleSeconds: chirpEventSettle.Seconds(),
+		AfterID:       *settledID,
+		RowLimit:      chirpEventBatchSize,
+	})
+	if err != nil {
+		return err
+	}
+
+	unpublished := []database.ListChirpEventsAfterRow{}
+	for _, dbEvent := range dbEvents {
+		if !published[dbEvent.ID] {
+			unpublished = append(unpublished, dbEvent)
+		}
+	}
+	events, err := cfg.chirpStreamEvents(ctx, unpublished)
+	if err != nil {
+		return err
+	}
+	cfg.chirpStream.Publish(events...)
+	for _, dbEvent := range unpublished {
+		published[dbEvent.ID] = true
+	}
+
+	for _, dbEvent := range dbEvents {
+		if !dbEvent.Settled {
+			break
+		}
+		*settledID = dbEvent.ID
+	}
+	for id := range published {
+		if id <= *settledID {
+			delete(published, id)
+		}
+	}
+	return nil
+}
+
+// chirpStreamEvents turns logged events into what's sent to stream clients.
+// Created chirps are sent in full as an anonymous viewer sees them, deleted
+// and hidden ones as a tombstone. A created event for a chirp that's already
+// gone or hidden is skipped, its deleted or hidden event comes after it.
+func (cfg *apiConfig) chirpStreamEvents(ctx context.Context, dbEvents []database.ListChirpEventsAfterRow) ([]stream.Event, error) {
+	chirpIDs := []uuid.UUID{}
+	for _, dbEvent := range dbEvents {
+		if dbEvent.Kind == chirpEventCreated {
+			chirpIDs = append(chirpIDs, dbEvent.ChirpID)
+		}
+	}
+
+	chirps := map[uuid.UUID]Chirp{}
+	if len(chirpIDs) > 0 {
+		dbChirps, err := cfg.db.GetChirpsByIDs(ctx, chirpIDs)
+		if err != nil {
+			return nil, err
+		}
+		converted, err := cfg.chirpsForViewer(ctx, uuid.NullUUID{}, dbChirps)
+		if err != nil {
+			return nil, err
+		}
+		for _, chirp := range converted {
+			chirps[chirp.ID] = chirp
+		}
+	}
+
+	events := []stream.Event{}
+	for _, dbEvent := range dbEvents {
+		var payload interface{}
+		switch dbEvent.Kind {
+		case chirpEventCreated:
+			chirp, ok := chirps[dbEvent.ChirpID]
+			if !ok || chirp.Hidden {
+				continue
+			}
+			payload = chirp
+		case chirpEventHidden:
+			payload = ChirpTombstone{ID: dbEvent.ChirpID, Hidden: true}
+		default:
+			payload = ChirpTombstone{ID: dbEvent.ChirpID, Deleted: true}
+		}
+		data, err := json.Marshal(payload)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, stream.Event{
+			ID:       dbEvent.ID,
+			Kind:     dbEvent.Kind,
+			ChirpID:  dbEvent.ChirpID,
+			UserID:   dbEvent.UserID,
+			ThreadID: dbEvent.ThreadID,
+			Tags:     dbEvent.Tags,
+			Data:     data,
+		})
+	}
+	return events, nil
+}
@@ -0,0 +1,464 @@
+package main
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/srinivassivaratri/Chirpy/internal/database"
+	"github.com/srinivassivaratri/Chirpy/internal/entities"
+	"github.com/srinivassivaratri/Chirpy/internal/notifications"
+)
+
+type Chirp struct {
+	ID        uuid.UUID      `json:"id"`
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+	UserID    uuid.UUID      `json:"user_id"`
+	Body      string         `json:"body"`
+	InReplyTo *uuid.UUID     `json:"in_reply_to"`
+	ThreadID  uuid.UUID      `json:"thread_id"`
+	LikeCount int32          `json:"like_count"`
+	LikedByMe bool           `json:"liked_by_me"`
+	RechirpOf *uuid.UUID     `json:"rechirp_of"`
+	Version   int32          `json:"version"`
+	Mentions  []ChirpMention `json:"mentions"`
+	Media     []ChirpMedia   `json:"media"`
+	Poll      *ChirpPoll     `json:"poll,omitempty"`
+	// Hidden means a moderator took the chirp out of public view. Only
+	// moderators are ever shown a hidden chirp.
+	Hidden bool `json:"hidden,omitempty"`
+	// Original is the rechirped Chirp, or a ChirpTombstone once it's deleted
+	Original interface{} `json:"original,omitempty"`
+}
+
+// ChirpMention links an @username in the body to the user it names. Start and
+// End are character offsets covering the "@username", End exclusive.
+type ChirpMention struct {
+	UserID   uuid.UUID `json:"user_id"`
+	Username string    `json:"username"`
+	Start    int32     `json:"start"`
+	End      int32     `json:"end"`
+}
+
+// ChirpTombstone stands in for a chirp that has since been deleted or hidden
+// by a moderator
+type ChirpTombstone struct {
+	ID      uuid.UUID `json:"id"`
+	Deleted bool      `json:"deleted"`
+	Hidden  bool      `json:"hidden,omitempty"`
+}
+
+func databaseChirpToChirp(dbChirp database.Chirp) Chirp {
+	chirp := Chirp{
+		ID:        dbChirp.ID,
+		CreatedAt: dbChirp.CreatedAt,
+		UpdatedAt: dbChirp.UpdatedAt,
+		UserID:    dbChirp.UserID,
+		Body:      dbChirp.Body,
+		ThreadID:  dbChirp.ThreadID,
+		LikeCount: dbChirp.LikeCount,
+		Version:   dbChirp.Version,
+		Mentions:  []ChirpMention{},
+		Media:     []ChirpMedia{},
+		Hidden:    dbChirp.HiddenAt.Valid,
+	}
+	if dbChirp.InReplyTo.Valid {
+		inReplyTo := dbChirp.InReplyTo.UUID
+		chirp.InReplyTo = &inReplyTo
+	}
+	if dbChirp.RechirpOf.Valid {
+		rechirpOf := dbChirp.RechirpOf.UUID
+		chirp.RechirpOf = &rechirpOf
+	}
+	return chirp
+}
+
+// createChirp saves a new chirp along with the hashtags and mentions in its
+// body and its poll, if any, and attaches the user's uploads in mediaIDs. It
+// returns errMediaNotFound unless every upload could be attached.
+func (cfg *apiConfig) createChirp(ctx context.Context, params database.CreateChirpParams, mediaIDs []uuid.UUID, poll *newPoll) (database.Chirp, error) {
+	tx, err := cfg.dbConn.BeginTx(ctx, nil)
+	if err != nil {
+		return database.Chirp{}, err
+	}
+	defer tx.Rollback()
+	qtx := cfg.db.WithTx(tx)
+
+	chirp, err := qtx.CreateChirp(ctx, params)
+	if err != nil {
+		return database.Chirp{}, err
+	}
+	if len(mediaIDs) > 0 {
+		attached, err := qtx.AttachMedia(ctx, database.AttachMediaParams{
+			ChirpID: chirp.ID,
+			Ids:     mediaIDs,
+			UserID:  chirp.UserID,
+		})
+		if err != nil {
+			return database.Chirp{}, err
+		}
+		if attached != int64(len(mediaIDs)) {
+			return database.Chirp{}, errMediaNotFound
+		}
+	}
+	if poll != nil {
+		err = qtx.CreatePoll(ctx, database.CreatePollParams{
+			ChirpID:  chirp.ID,
+			ClosesAt: poll.ClosesAt,
+		})
+		if err != nil {
+			return database.Chirp{}, err
+		}
+		err = qtx.AddPollOptions(ctx, database.AddPollOptionsParams{
+			ChirpID: chirp.ID,
+			Texts:   poll.Options,
+		})
+		if err != nil {
+			return database.Chirp{}, err
+		}
+	}
+	err = saveChirpHashtags(ctx, qtx, chirp)
+	if err != nil {
+		return database.Chirp{}, err
+	}
+	mentioned, err := saveChirpMentions(ctx, qtx, chirp, map[uuid.UUID]bool{})
+	if err != nil {
+		return database.Chirp{}, err
+	}
+	err = qtx.CreateChirpEvent(ctx, database.CreateChirpEventParams{
+		Kind:    chirpEventCreated,
+		ChirpID: chirp.ID,
+	})
+	if err != nil {
+		return database.Chirp{}, err
+	}
+	err = tx.Commit()
+	if err != nil {
+		return database.Chirp{}, err
+	}
+	cfg.notifyMentions(chirp, mentioned)
+	cfg.publishChirpEvent(ctx, chirpEventCreated, chirp.ID)
+	return chirp, nil
+}
+
+// updateChirpBody edits a chirp and replaces its hashtags and mentions with the
+// ones in the new body. Only users the edit newly mentions are notified.
+func (cfg *apiConfig) updateChirpBody(ctx context.Context, params database.UpdateChirpBodyParams) (database.Chirp, error) {
+	tx, err := cfg.dbConn.BeginTx(ctx, nil)
+	if err != nil {
+		return database.Chirp{}, err
+	}
+	defer tx.Rollback()
+	qtx := cfg.db.WithTx(tx)
+
+	chirp, err := qtx.UpdateChirpBody(ctx, params)
+	if err != nil {
+		return database.Chirp{}, err
+	}
+	err = qtx.DeleteChirpHashtags(ctx, chirp.ID)
+	if err != nil {
+		return database.Chirp{}, err
+	}
+	err = saveChirpHashtags(ctx, qtx, chirp)
+	if err != nil {
+		return database.Chirp{}, err
+	}
+
+	oldMentions, err := qtx.ListChirpMentions(ctx, []uuid.UUID{chirp.ID})
+	if err != nil {
+		return database.Chirp{}, err
+	}
+	notified := map[uuid.UUID]bool{}
+	for _, mention := range oldMentions {
+		notified[mention.UserID] = true
+	}
+	err = qtx.DeleteChirpMentions(ctx, chirp.ID)
+	if err != nil {
+		return database.Chirp{}, err
+	}
+	mentioned, err := saveChirpMentions(ctx, qtx, chirp, notified)
+	if err != nil {
+		return database.Chirp{}, err
+	}
+	err = tx.Commit()
+	if err != nil {
+		return database.Chirp{}, err
+	}
+	cfg.notifyMentions(chirp, mentioned)
+	return chirp, nil
+}
+
+// deleteChirp deletes a chirp and logs it so live streams on every instance drop it
+func (cfg *apiConfig) deleteChirp(ctx context.Context, chirpID uuid.UUID) error {
+	tx, err := cfg.dbConn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	qtx := cfg.db.WithTx(tx)
+
+	err = removeChirp(ctx, qtx, chirpID)
+	if err != nil {
+		return err
+	}
+	err = tx.Commit()
+	if err != nil {
+		return err
+	}
+	cfg.publishChirpEvent(ctx, chirpEventDeleted, chirpID)
+	return nil
+}
+
+// removeChirp logs a chirp's deleted event and deletes it. The caller
+// publishes the event once its transaction commits.
+func removeChirp(ctx context.Context, q *database.Queries, chirpID uuid.UUID) error {
+	err := q.CreateChirpEvent(ctx, database.CreateChirpEventParams{
+		Kind:    chirpEventDeleted,
+		ChirpID: chirpID,
+	})
+	if err != nil {
+		return err
+	}
+	return q.DeleteChirp(ctx, chirpID)
+}
+
+// hideChirp hides a chirp and logs it so live streams drop it like a deleted
+// one. The caller publishes the event once its transaction commits.
+func hideChirp(ctx context.Context, q *database.Queries, chirpID uuid.UUID) error {
+	_, err := q.HideChirp(ctx, chirpID)
+	if err != nil {
+		return err
+	}
+	return q.CreateChirpEvent(ctx, database.CreateChirpEventParams{
+		Kind:    chirpEventHidden,
+		ChirpID: chirpID,
+	})
+}
+
+func saveChirpHashtags(ctx context.Context, q *database.Queries, chirp database.Chirp) error {
+	tags := entities.Hashtags(chirp.Body)
+	if len(tags) == 0 {
+		return nil
+	}
+	err := q.CreateHashtags(ctx, tags)
+	if err != nil {
+		return err
+	}
+	return q.AddChirpHashtags(ctx, database.AddChirpHashtagsParams{
+		ChirpID:   chirp.ID,
+		Tags:      tags,
+		CreatedAt: chirp.CreatedAt,
+	})
+}
+
+// saveChirpMentions links each @username in the chirp's body to its user and
+// returns the users to notify, skipping anyone in notified. Usernames that
+// don't belong to anyone, or whose owner has blocked the author, stay plain
+// text.
+func saveChirpMentions(ctx context.Context, q *database.Queries, chirp database.Chirp, notified map[uuid.UUID]bool) ([]uuid.UUID, error) {
+	mentions := entities.Mentions(chirp.Body)
+	if len(mentions) == 0 {
+		return nil, nil
+	}
+
+	usernames := []string{}
+	for _, mention := range mentions {
+		usernames = append(usernames, strings.ToLower(mention.Username))
+	}
+	users, err := q.GetUsersByUsernames(ctx, usernames)
+	if err != nil {
+		return nil, err
+	}
+	candidateIDs := []uuid.UUID{}
+	for _, user := range users {
+		candidateIDs = append(candidateIDs, user.ID)
+	}
+	blockerIDs, err := q.ListBlockersOf(ctx, database.ListBlockersOfParams{
+		BlockedID: chirp.UserID,
+		UserIds:   candidateIDs,
+	})
+	if err != nil {
+		return nil, err
+	}
+	blockedBy := map[uuid.UUID]bool{}
+	for _, id := range blockerIDs {
+		blockedBy[id] = true
+	}
+
+	userIDs := map[string]uuid.UUID{}
+	for _, user := range users {
+		if blockedBy[user.ID] {
+			continue
+		}
+		userIDs[strings.ToLower(user.Username.String)] = user.ID
+	}
+
+	params := database.AddChirpMentionsParams{ChirpID: chirp.ID}
+	recipients := []uuid.UUID{}
+	for _, mention := range mentions {
+		userID, ok := userIDs[strings.ToLower(mention.Username)]
+		if !ok {
+			continue
+		}
+		params.UserIds = append(params.UserIds, userID)
+		params.StartIndexes = append(params.StartIndexes, int32(mention.Start))
+		params.EndIndexes = append(params.EndIndexes, int32(mention.End))
+		if !notified[userID] {
+			notified[userID] = true
+			recipients = append(recipients, userID)
+		}
+	}
+	if len(params.UserIds) == 0 {
+		return nil, nil
+	}
+	err = q.AddChirpMentions(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+	return recipients, nil
+}
+
+func (cfg *apiConfig) notifyMentions(chirp database.Chirp, userIDs []uuid.UUID) {
+	for _, userID := range userIDs {
+		cfg.notifier.Notify(notifications.Notification{
+			UserID:  userID,
+			ActorID: uuid.NullUUID{UUID: chirp.UserID, Valid: true},
+			Kind:    notifications.KindMention,
+			ChirpID: uuid.NullUUID{UUID: chirp.ID, Valid: true},
+		})
+	}
+}
+
+// chirpsForViewer converts chirps for the response and fills in the parts
+// that need other rows or depend on who is looking at them. It makes the
+// same number of queries however many chirps there are.
+func (cfg *apiConfig) chirpsForViewer(ctx context.Context, viewerID uuid.NullUUID, dbChirps []database.Chirp) ([]Chirp, error) {
+	chirps := []Chirp{}
+	originalIDs := []uuid.UUID{}
+	for _, dbChirp := range dbChirps {
+		chirps = append(chirps, databaseChirpToChirp(dbChirp))
+		if dbChirp.RechirpOf.Valid {
+			originalIDs = append(originalIDs, dbChirp.RechirpOf.UUID)
+		}
+	}
+
+	// Rechirps embed the chirp they share, one level deep
+	originals := map[uuid.UUID]*Chirp{}
+	hiddenOriginals := map[uuid.UUID]bool{}
+	if len(originalIDs) > 0 {
+		dbOriginals, err := cfg.db.GetChirpsByIDs(ctx, originalIDs)
+		if err != nil {
+			return nil, err
+		}
+		for _, dbOriginal := range dbOriginals {
+			if dbOriginal.HiddenAt.Valid {
+				hiddenOriginals[dbOriginal.ID] = true
+				continue
+			}
+			original := databaseChirpToChirp(dbOriginal)
+			originals[original.ID] = &original
+		}
+	}
+
+	chirpIDs := []uuid.UUID{}
+	for _, chirp := range chirps {
+		chirpIDs = append(chirpIDs, chirp.ID)
+	}
+	for id := range originals {
+		chirpIDs = append(chirpIDs, id)
+	}
+
+	if len(chirpIDs) > 0 {
+		dbMentions, err := cfg.db.ListChirpMentions(ctx, chirpIDs)
+		if err != nil {
+			return nil, err
+		}
+		mentions := map[uuid.UUID][]ChirpMention{}
+		for _, dbMention := range dbMentions {
+			mentions[dbMention.ChirpID] = append(mentions[dbMention.ChirpID], ChirpMention{
+				UserID:   dbMention.UserID,
+				Username: dbMention.Username.String,
+				Start:    dbMention.StartIndex,
+				End:      dbMention.EndIndex,
+			})
+		}
+		for i := range chirps {
+			if chirpMentions, ok := mentions[chirps[i].ID]; ok {
+				chirps[i].Mentions = chirpMentions
+			}
+		}
+		for id, original := range originals {
+			if chirpMentions, ok := mentions[id]; ok {
+				original.Mentions = chirpMentions
+			}
+		}
+
+		dbMedia, err := cfg.db.ListChirpMedia(ctx, chirpIDs)
+		if err != nil {
+			return nil, err
+		}
+		chirpMedia := map[uuid.UUID][]ChirpMedia{}
+		for _, m := range dbMedia {
+			chirpMedia[m.ChirpID.UUID] = append(chirpMedia[m.ChirpID.UUID], cfg.databaseMediaToChirpMedia(m))
+		}
+		for i := range chirps {
+			if attached, ok := chirpMedia[chirps[i].ID]; ok {
+				chirps[i].Media = attached
+			}
+		}
+		for id, original := range originals {
+			if attached, ok := chirpMedia[id]; ok {
+				original.Media = attached
+			}
+		}
+
+		chirpPolls, err := cfg.pollsForViewer(ctx, viewerID, chirpIDs)
+		if err != nil {
+			return nil, err
+		}
+		for i := range chirps {
+			chirps[i].Poll = chirpPolls[chirps[i].ID]
+		}
+		for id, original := range originals {
+			original.Poll = chirpPolls[id]
+		}
+	}
+
+	if viewerID.Valid && len(chirps) > 0 {
+		likedIDs, err := cfg.db.GetLikedChirpIDs(ctx, database.GetLikedChirpIDsParams{
+			UserID:   viewerID.UUID,
+			ChirpIds: chirpIDs,
+		})
+		if err != nil {
+			return nil, err
+		}
+		liked := map[uuid.UUID]bool{}
+		for _, id := range likedIDs {
+			liked[id] = true
+		}
+		for i := range chirps {
+			chirps[i].LikedByMe = liked[chirps[i].ID]
+		}
+		for id, original := range originals {
+			original.LikedByMe = liked[id]
+		}
+	}
+
+	for i := range chirps {
+		if chirps[i].RechirpOf == nil {
+			continue
+		}
+		if original, ok := originals[*chirps[i].RechirpOf]; ok {
+			chirps[i].Original = *original
+			continue
+		}
+		if hiddenOriginals[*chirps[i].RechirpOf] {
+			chirps[i].Original = ChirpTombstone{ID: *chirps[i].RechirpOf, Hidden: true}
+			continue
+		}
+		chirps[i].Original = ChirpTombstone{ID: *chirps[i].RechirpOf, Deleted: true}
+	}
+	return chirps, nil
+}
@@ -0,0 +1,162 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	"github.com/google/uuid"
+	"github.com/srinivassivaratri/Chirpy/internal/database"
+	"github.com/srinivassivaratri/Chirpy/internal/filter"
+	"github.com/srinivassivaratri/Chirpy/internal/moderation"
+	"github.com/srinivassivaratri/Chirpy/internal/pubsub"
+)
+
+// contentFiltersChannel is the pubsub channel that tells every instance to
+// reload its content filters
+const contentFiltersChannel = "content_filters"
+
+var errBodyRejected = errors.New("Contains a word that isn't allowed")
+
+// defaultFilters are used when there's no filters file
+func defaultFilters() []filter.Filter {
+	return []filter.Filter{
+		filter.NewWordList("profanity", filter.ActionMask, []string{"kerfuffle", "sharbert", "fornax"}),
+	}
+}
+
+// loadFilters reads the word lists from the filters file, or the defaults
+// without one, followed by the lists in the database
+func (cfg *apiConfig) loadFilters(ctx context.Context) ([]filter.Filter, error) {
+	filters := defaultFilters()
+	if cfg.filtersFile != "" {
+		fileFilters, err := filter.LoadFile(cfg.filtersFile)
+		if err != nil {
+			return nil, err
+		}
+		filters = fileFilters
+	}
+
+	dbFilters, err := cfg.db.ListContentFilters(ctx)
+	if err != nil {
+		return nil, err
+	}
+	dbWords, err := cfg.db.ListContentFilterWords(ctx)
+	if err != nil {
+		return nil, err
+	}
+	words := map[string][]string{}
+	for _, dbWord := range dbWords {
+		words[dbWord.FilterName] = append(words[dbWord.FilterName], dbWord.Word)
+	}
+	for _, dbFilter := range dbFilters {
+		filters = append(filters, filter.NewWordList(dbFilter.Name, filter.Action(dbFilter.Action), words[dbFilter.Name]))
+	}
+	return filters, nil
+}
+
+// reloadFilters swaps in freshly loaded filters. If loading fails the old
+// ones stay in place.
+func (cfg *apiConfig) reloadFilters(ctx context.Context) error {
+	filters, err := cfg.loadFilters(ctx)
+	if err != nil {
+		return err
+	}
+	cfg.contentFilter.Replace(filters)
+	return nil
+}
+
+// reloadFiltersLoop reloads the filters on SIGHUP, so an edited filters file
+// takes effect without a restart, and whenever another instance changes the
+// lists in the database
+func (cfg *apiConfig) reloadFiltersLoop(ctx context.Context) {
+	hangups := make(chan os.Signal, 1)
+	signal.Notify(hangups, syscall.SIGHUP)
+	defer signal.Stop(hangups)
+
+	var changes <-chan pubsub.Message
+	sub, err := cfg.pubsub.Subscribe(contentFiltersChannel)
+	if err != nil {
+		log.Printf("Error subscribing to content filter changes: %s", err)
+	} else {
+		defer sub.Close()
+		changes = sub.Messages()
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-hangups:
+		case <-changes:
+		}
+
+		err := cfg.reloadFilters(ctx)
+		if err != nil {
+			log.Printf("Error reloading content filters: %s", err)
+			continue
+		}
+		log.Printf("Reloaded content filters")
+	}
+}
+
+// publishFiltersChanged tells every instance to reload its filters
+func (cfg *apiConfig) publishFiltersChanged(ctx context.Context) {
+	err := cfg.pubsub.Publish(ctx, contentFiltersChannel, "")
+	if err != nil {
+		log.Printf("Error publishing content filter change: %s", err)
+	}
+}
+
+// flagForReview files a report for moderators about text a flag filter
+// matched. Reports about messages are filed against the sender and leave the
+// words out, since moderators can't read direct messages.
+func (cfg *apiConfig) flagForReview(ctx context.Context, chirpID uuid.NullUUID, userID uuid.UUID, flagged []filter.Match) {
+	if len(flagged) == 0 {
+		return
+	}
+
+	target := moderation.TargetUser
+	prefix := "Flagged by content filters in a direct message: "
+	if chirpID.Valid {
+		target = moderation.TargetChirp
+		prefix = "Flagged by content filters: "
+	}
+	seen := map[string]bool{}
+	matches := []string{}
+	for _, match := range flagged {
+		description := match.Filter
+		if chirpID.Valid {
+			description = fmt.Sprintf("%q (%s)", match.Word, match.Filter)
+		}
+		if seen[description] {
+			continue
+		}
+		seen[description] = true
+		matches = append(matches, description)
+	}
+	details := prefix + strings.Join(matches, ", ")
+	if len(details) > maxReportDetailsLength {
+		details = strings.ToValidUTF8(details[:maxReportDetailsLength], "")
+	}
+
+	_, err := cfg.db.CreateReport(ctx, database.CreateReportParams{
+		TargetKind: string(target),
+		ChirpID:    chirpID,
+		UserID:     userID,
+		Reason:     string(moderation.ReasonFilter),
+		Details:    details,
+	})
+	// Already waiting for a moderator
+	if isUniqueViolation(err) {
+		return
+	}
+	if err != nil {
+		log.Printf("Error filing content filter report: %s", err)
+	}
+}
@@ -0,0 +1,153 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/srinivassivaratri/Chirpy/internal/database"
+)
+
+// Who can start a conversation with a user
+const (
+	dmPolicyEveryone  = "everyone"
+	dmPolicyFollowing = "following"
+	dmPolicyNobody    = "nobody"
+)
+
+// maxConversationParticipants counts the user who starts the conversation
+const maxConversationParticipants = 8
+
+var errInvalidDMPolicy = errors.New("DM policy must be everyone, following or nobody")
+
+type Conversation struct {
+	ID           uuid.UUID                 `json:"id"`
+	CreatedAt    time.Time                 `json:"created_at"`
+	UpdatedAt    time.Time                 `json:"updated_at"`
+	IsGroup      bool                      `json:"is_group"`
+	Participants []ConversationParticipant `json:"participants"`
+	LastMessage  *Message                  `json:"last_message"`
+	UnreadCount  int64                     `json:"unread_count"`
+}
+
+// ConversationParticipant doubles as the participant's read receipt:
+// everything up to LastReadMessageID has been read
+type ConversationParticipant struct {
+	UserID            uuid.UUID  `json:"user_id"`
+	Username          string     `json:"username,omitempty"`
+	JoinedAt          time.Time  `json:"joined_at"`
+	LastReadMessageID *uuid.UUID `json:"last_read_message_id"`
+	LastReadAt        *time.Time `json:"last_read_at"`
+}
+
+type Message struct {
+	ID             uuid.UUID `json:"id"`
+	CreatedAt      time.Time `json:"created_at"`
+	ConversationID uuid.UUID `json:"conversation_id"`
+	SenderID       uuid.UUID `json:"sender_id"`
+	Body           string    `json:"body"`
+}
+
+func databaseMessageToMessage(dbMessage database.Message) Message {
+	return Message{
+		ID:             dbMessage.ID,
+		CreatedAt:      dbMessage.CreatedAt,
+		ConversationID: dbMessage.ConversationID,
+		SenderID:       dbMessage.SenderID,
+		Body:           dbMessage.Body,
+	}
+}
+
+func validDMPolicy(policy string) bool {
+	return policy == dmPolicyEveryone || policy == dmPolicyFollowing || policy == dmPolicyNobody
+}
+
+// directConversationKey is the same for a pair of users whichever of them
+// starts the conversation
+func directConversationKey(a, b uuid.UUID) string {
+	ids := []string{a.String(), b.String()}
+	sort.Strings(ids)
+	return strings.Join(ids, ":")
+}
+
+// canMessage reports whether recipient's DM policy lets sender start a
+// conversation with them
+func (cfg *apiConfig) canMessage(ctx context.Context, senderID uuid.UUID, recipient database.User) (bool, error) {
+	switch recipient.DmPolicy {
+	case dmPolicyEveryone:
+		return true, nil
+	case dmPolicyFollowing:
+		return cfg.db.IsFollowing(ctx, database.IsFollowingParams{
+			FollowerID: recipient.ID,
+			FolloweeID: senderID,
+		})
+	default:
+		return false, nil
+	}
+}
+
+// conversationsForUser converts conversations for the response, adding their
+// participants and latest message in the same number of queries however many
+// conversations there are
+func (cfg *apiConfig) conversationsForUser(ctx context.Context, dbConversations []database.Conversation, unreadCounts []int64) ([]Conversation, error) {
+	conversations := []Conversation{}
+	conversationIDs := []uuid.UUID{}
+	for i, dbConversation := range dbConversations {
+		conversations = append(conversations, Conversation{
+			ID:           dbConversation.ID,
+			CreatedAt:    dbConversation.CreatedAt,
+			UpdatedAt:    dbConversation.UpdatedAt,
+			IsGroup:      !dbConversation.DmKey.Valid,
+			Participants: []ConversationParticipant{},
+			UnreadCount:  unreadCounts[i],
+		})
+		conversationIDs = append(conversationIDs, dbConversation.ID)
+	}
+	if len(conversationIDs) == 0 {
+		return conversations, nil
+	}
+
+	dbParticipants, err := cfg.db.ListConversationParticipants(ctx, conversationIDs)
+	if err != nil {
+		return nil, err
+	}
+	participants := map[uuid.UUID][]ConversationParticipant{}
+	for _, dbParticipant := range dbParticipants {
+		participant := ConversationParticipant{
+			UserID:   dbParticipant.UserID,
+			Username: dbParticipant.Username.String,
+			JoinedAt: dbParticipant.JoinedAt,
+		}
+		if dbParticipant.LastReadMessageID.Valid {
+			lastReadMessageID := dbParticipant.LastReadMessageID.UUID
+			participant.LastReadMessageID = &lastReadMessageID
+		}
+		if dbParticipant.LastReadAt.Valid {
+			lastReadAt := dbParticipant.LastReadAt.Time
+			participant.LastReadAt = &lastReadAt
+		}
+		participants[dbParticipant.ConversationID] = append(participants[dbParticipant.ConversationID], participant)
+	}
+
+	dbMessages, err := cfg.db.ListLatestMessages(ctx, conversationIDs)
+	if err != nil {
+		return nil, err
+	}
+	latest := map[uuid.UUID]Message{}
+	for _, dbMessage := range dbMessages {
+		latest[dbMessage.ConversationID] = databaseMessageToMessage(dbMessage)
+	}
+
+	for i := range conversations {
+		if conversationParticipants, ok := participants[conversations[i].ID]; ok {
+			conversations[i].Participants = conversationParticipants
+		}
+		if message, ok := latest[conversations[i].ID]; ok {
+			conversations[i].LastMessage = &message
+		}
+	}
+	return conversations, nil
+}
@@ -12,3 +12,7 @@ require github.com/joho/godotenv v1.5.1
 require golang.org/x/crypto v0.29.0
 
 require github.com/golang-jwt/jwt/v5 v5.2.1
+
+require github.com/gorilla/websocket v1.5.3
+
+require golang.org/x/sys v0.27.0 // indirect
@@ -0,0 +1,192 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/srinivassivaratri/Chirpy/internal/auth"
+	"github.com/srinivassivaratri/Chirpy/internal/database"
+	"github.com/srinivassivaratri/Chirpy/internal/pagination"
+)
+
+// Block is one entry in the list of users someone has blocked
+type Block struct {
+	UserID    uuid.UUID `json:"user_id"`
+	BlockedAt time.Time `json:"blocked_at"`
+}
+
+// handlerBlocksCreate blocks a user. They can't follow, reply to, mention or
+// message the blocker any more, and their chirps are hidden from the blocker.
+func (cfg *apiConfig) handlerBlocksCreate(w http.ResponseWriter, r *http.Request) {
+	blockedIDString := r.PathValue("userID")
+	blockedID, err := uuid.Parse(blockedIDString)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid user ID", err)
+		return
+	}
+
+	token, err := auth.GetBearerToken(r.Header)
+	if err != nil {
+		respondWithAuthError(w, err)
+		return
+	}
+	userID, err := cfg.validateJWT(r.Context(), token)
+	if err != nil {
+		respondWithAuthError(w, err)
+		return
+	}
+
+	if blockedID == userID {
+		respondWithError(w, http.StatusBadRequest, "You can't block yourself", nil)
+		return
+	}
+
+	_, err = cfg.db.GetUser(r.Context(), blockedID)
+	if errors.Is(err, sql.ErrNoRows) {
+		respondWithError(w, http.StatusNotFound, "Couldn't find user", err)
+		return
+	}
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't get user", err)
+		return
+	}
+
+	err = cfg.blockUser(r.Context(), userID, blockedID)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't block user", err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// blockUser saves the block and drops any follows between the two users
+func (cfg *apiConfig) blockUser(ctx context.Context, userID, blockedID uuid.UUID) error {
+	tx, err := cfg.dbConn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	qtx := cfg.db.WithTx(tx)
+
+	err = qtx.CreateBlock(ctx, database.CreateBlockParams{
+		BlockerID: userID,
+		BlockedID: blockedID,
+	})
+	if err != nil {
+		return err
+	}
+	err = qtx.DeleteFollowsBetween(ctx, database.DeleteFollowsBetweenParams{
+		UserID:  userID,
+		OtherID: blockedID,
+	})
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (cfg *apiConfig) handlerBlocksDelete(w http.ResponseWriter, r *http.Request) {
+	blockedIDString := r.PathValue("userID")
+	blockedID, err := uuid.Parse(blockedIDString)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid user ID", err)
+		return
+	}
+
+	token, err := auth.GetBearerToken(r.Header)
+	if err != nil {
+		respondWithAuthError(w, err)
+		return
+	}
+	userID, err := cfg.validateJWT(r.Context(), token)
+	if err != nil {
+		respondWithAuthError(w, err)
+		return
+	}
+
+	// Unblocking doesn't bring back the follows the block removed
+	err = cfg.db.DeleteBlock(r.Context(), database.DeleteBlockParams{
+		BlockerID: userID,
+		BlockedID: blockedID,
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't unblock user", err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handlerBlocksGet lists the users the caller has blocked. Nobody else can
+// see who someone has blocked.
+func (cfg *apiConfig) handlerBlocksGet(w http.ResponseWriter, r *http.Request) {
+	type response struct {
+		Users      []Block `json:"users"`
+		NextCursor string  `json:"next_cursor,omitempty"`
+		PrevCursor string  `json:"prev_cursor,omitempty"`
+	}
+
+	token, err := auth.GetBearerToken(r.Header)
+	if err != nil {
+		respondWithAuthError(w, err)
+		return
+	}
+	userID, err := cfg.validateJWT(r.Context(), token)
+	if err != nil {
+		respondWithAuthError(w, err)
+		return
+	}
+
+	limit, cursor, err := parsePage(r)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error(), err)
+		return
+	}
+	cursorCreatedAt, cursorID := cursorPosition(cursor)
+
+	// Newest blocks first
+	var dbBlocks []database.Block
+	if walkingBack(cursor) {
+		dbBlocks, err = cfg.db.ListBlocksAsc(r.Context(), database.ListBlocksAscParams{
+			UserID:          userID,
+			CursorCreatedAt: cursorCreatedAt,
+			CursorID:        cursorID,
+			RowLimit:        int32(limit + 1),
+		})
+	} else {
+		dbBlocks, err = cfg.db.ListBlocksDesc(r.Context(), database.ListBlocksDescParams{
+			UserID:          userID,
+			CursorCreatedAt: cursorCreatedAt,
+			CursorID:        cursorID,
+			RowLimit:        int32(limit + 1),
+		})
+	}
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't get blocked users", err)
+		return
+	}
+
+	dbBlocks, nextCursor, prevCursor := pagination.Paginate(dbBlocks, limit, cursor, func(b database.Block) pagination.Cursor {
+		return pagination.Cursor{CreatedAt: b.CreatedAt, ID: b.BlockedID}
+	})
+
+	users := []Block{}
+	for _, dbBlock := range dbBlocks {
+		users = append(users, Block{
+			UserID:    dbBlock.BlockedID,
+			BlockedAt: dbBlock.CreatedAt,
+		})
+	}
+
+	setLinkHeader(w, r, nextCursor, prevCursor)
+	respondWithJSON(w, http.StatusOK, response{
+		Users:      users,
+		NextCursor: nextCursor,
+		PrevCursor: prevCursor,
+	})
+}
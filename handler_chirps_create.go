@@ -1,38 +1,38 @@
 package main
 
 import (
+	"database/sql"
 	"encoding/json"
 	"errors"
 	"net/http"
-	"strings"
-	"time"
 
 	"github.com/google/uuid"
 	"github.com/srinivassivaratri/Chirpy/internal/auth"
 	"github.com/srinivassivaratri/Chirpy/internal/database"
+	"github.com/srinivassivaratri/Chirpy/internal/filter"
+	"github.com/srinivassivaratri/Chirpy/internal/notifications"
 )
 
-type Chirp struct {
-	ID        uuid.UUID `json:"id"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
-	UserID    uuid.UUID `json:"user_id"`
-	Body      string    `json:"body"`
-}
-
 func (cfg *apiConfig) handlerChirpsCreate(w http.ResponseWriter, r *http.Request) {
+	type pollParameters struct {
+		Options         []string `json:"options"`
+		DurationSeconds int      `json:"duration_seconds"`
+	}
 	type parameters struct {
-		Body string `json:"body"`
+		Body      string          `json:"body"`
+		InReplyTo *uuid.UUID      `json:"in_reply_to"`
+		MediaIDs  []uuid.UUID     `json:"media_ids"`
+		Poll      *pollParameters `json:"poll"`
 	}
 
 	token, err := auth.GetBearerToken(r.Header)
 	if err != nil {
-		respondWithError(w, http.StatusUnauthorized, "Couldn't find JWT", err)
+		respondWithAuthError(w, err)
 		return
 	}
-	userID, err := auth.ValidateJWT(token, cfg.jwtSecret)
+	userID, err := cfg.validateJWT(r.Context(), token)
 	if err != nil {
-		respondWithError(w, http.StatusUnauthorized, "Couldn't validate JWT", err)
+		respondWithAuthError(w, err)
 		return
 	}
 
@@ -44,53 +44,104 @@ func (cfg *apiConfig) handlerChirpsCreate(w http.ResponseWriter, r *http.Request
 		return
 	}
 
-	cleaned, err := validateChirp(params.Body)
+	filtered, err := cfg.validateChirp(params.Body)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error(), err)
+		return
+	}
+	err = checkChirpMedia(params.MediaIDs)
 	if err != nil {
 		respondWithError(w, http.StatusBadRequest, err.Error(), err)
 		return
 	}
+	var poll *newPoll
+	if params.Poll != nil {
+		var pollFlagged []filter.Match
+		poll, pollFlagged, err = cfg.checkPoll(params.Poll.Options, params.Poll.DurationSeconds)
+		if err != nil {
+			respondWithError(w, http.StatusBadRequest, err.Error(), err)
+			return
+		}
+		filtered.Flagged = append(filtered.Flagged, pollFlagged...)
+	}
+
+	// Replies join the thread of the chirp they answer
+	var parent database.Chirp
+	inReplyTo := uuid.NullUUID{}
+	if params.InReplyTo != nil {
+		parent, err = cfg.db.GetChirp(r.Context(), *params.InReplyTo)
+		if errors.Is(err, sql.ErrNoRows) {
+			respondWithError(w, http.StatusBadRequest, "Couldn't find chirp to reply to", err)
+			return
+		}
+		if err != nil {
+			respondWithError(w, http.StatusInternalServerError, "Couldn't get chirp to reply to", err)
+			return
+		}
+		if parent.HiddenAt.Valid {
+			respondWithError(w, http.StatusBadRequest, "Couldn't find chirp to reply to", nil)
+			return
+		}
+		blocked, err := cfg.db.IsBlocked(r.Context(), database.IsBlockedParams{
+			BlockerID: parent.UserID,
+			BlockedID: userID,
+		})
+		if err != nil {
+			respondWithError(w, http.StatusInternalServerError, "Couldn't check blocks", err)
+			return
+		}
+		if blocked {
+			respondWithError(w, http.StatusForbidden, "You can't reply to this chirp", nil)
+			return
+		}
+		inReplyTo = uuid.NullUUID{UUID: parent.ID, Valid: true}
+	}
 
-	chirp, err := cfg.db.CreateChirp(r.Context(), database.CreateChirpParams{
-		UserID: userID,
-		Body:   cleaned,
-	})
+	chirp, err := cfg.createChirp(r.Context(), database.CreateChirpParams{
+		UserID:    userID,
+		Body:      filtered.Text,
+		InReplyTo: inReplyTo,
+	}, params.MediaIDs, poll)
+	if errors.Is(err, errMediaNotFound) {
+		respondWithError(w, http.StatusBadRequest, err.Error(), err)
+		return
+	}
 	if err != nil {
 		respondWithError(w, http.StatusInternalServerError, "Couldn't create chirp", err)
 		return
 	}
+	cfg.flagForReview(r.Context(), uuid.NullUUID{UUID: chirp.ID, Valid: true}, userID, filtered.Flagged)
 
-	respondWithJSON(w, http.StatusCreated, Chirp{
-		ID:        chirp.ID,
-		CreatedAt: chirp.CreatedAt,
-		UpdatedAt: chirp.UpdatedAt,
-		UserID:    chirp.UserID,
-		Body:      chirp.Body,
-	})
-}
-
-func validateChirp(body string) (string, error) {
-	const maxChirpLength = 140
-	if len(body) > maxChirpLength {
-		return "", errors.New("Chirp is too long")
+	if inReplyTo.Valid {
+		cfg.notifier.Notify(notifications.Notification{
+			UserID:  parent.UserID,
+			ActorID: uuid.NullUUID{UUID: userID, Valid: true},
+			Kind:    notifications.KindReply,
+			ChirpID: uuid.NullUUID{UUID: chirp.ID, Valid: true},
+		})
 	}
 
-	badWords := map[string]struct{}{
-		"kerfuffle": {},
-		"sharbert":  {},
-		"fornax":    {},
+	chirps, err := cfg.chirpsForViewer(r.Context(), uuid.NullUUID{UUID: userID, Valid: true}, []database.Chirp{chirp})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't get chirp", err)
+		return
 	}
-	cleaned := getCleanedBody(body, badWords)
-	return cleaned, nil
+
+	respondWithJSON(w, http.StatusCreated, chirps[0])
 }
 
-func getCleanedBody(body string, badWords map[string]struct{}) string {
-	words := strings.Split(body, " ")
-	for i, word := range words {
-		loweredWord := strings.ToLower(word)
-		if _, ok := badWords[loweredWord]; ok {
-			words[i] = "****"
-		}
+// validateChirp checks a chirp's length and runs it through the content
+// filters. Length is counted in characters as readers see them, so emoji
+// count once.
+func (cfg *apiConfig) validateChirp(body string) (filter.Result, error) {
+	const maxChirpLength = 140
+	if filter.Length(body) > maxChirpLength {
+		return filter.Result{}, errors.New("Chirp is too long")
+	}
+
+	result, err := cfg.contentFilter.Apply(body)
+	if err != nil {
+		return filter.Result{}, errBodyRejected
 	}
-	cleaned := strings.Join(words, " ")
-	return cleaned
+	return result, nil
 }
@@ -5,6 +5,7 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/srinivassivaratri/Chirpy/internal/database"
+	"github.com/srinivassivaratri/Chirpy/internal/pagination"
 )
 
 func (cfg *apiConfig) handlerChirpsGet(w http.ResponseWriter, r *http.Request) {
@@ -15,22 +16,37 @@ func (cfg *apiConfig) handlerChirpsGet(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	viewerID, err := cfg.viewerID(r)
+	if err != nil {
+		respondWithAuthError(w, err)
+		return
+	}
+
 	dbChirp, err := cfg.db.GetChirp(r.Context(), chirpID)
 	if err != nil {
 		respondWithError(w, http.StatusNotFound, "Couldn't get chirp", err)
 		return
 	}
+	if cfg.respondIfHidden(w, r, viewerID, dbChirp) {
+		return
+	}
 
-	respondWithJSON(w, http.StatusOK, Chirp{
-		ID:        dbChirp.ID,
-		CreatedAt: dbChirp.CreatedAt,
-		UpdatedAt: dbChirp.UpdatedAt,
-		UserID:    dbChirp.UserID,
-		Body:      dbChirp.Body,
-	})
+	chirps, err := cfg.chirpsForViewer(r.Context(), viewerID, []database.Chirp{dbChirp})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't get chirp", err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, chirps[0])
 }
 
 func (cfg *apiConfig) handlerChirpsRetrieve(w http.ResponseWriter, r *http.Request) {
+	type response struct {
+		Chirps     []Chirp `json:"chirps"`
+		NextCursor string  `json:"next_cursor,omitempty"`
+		PrevCursor string  `json:"prev_cursor,omitempty"`
+	}
+
 	// Get query parameters
 	authorIDStr := r.URL.Query().Get("author_id")
 	sortOrder := r.URL.Query().Get("sort")
@@ -46,54 +62,75 @@ func (cfg *apiConfig) handlerChirpsRetrieve(w http.ResponseWriter, r *http.Reque
 		sortOrder = "asc"
 	}
 
-	var dbChirps []database.Chirp
-	var err error
-
-	// Get chirps with appropriate sort order
-	if sortOrder == "desc" {
-		dbChirps, err = cfg.db.GetChirpsDesc(r.Context())
-	} else {
-		dbChirps, err = cfg.db.GetChirpsAsc(r.Context())
+	viewerID, err := cfg.viewerID(r)
+	if err != nil {
+		respondWithAuthError(w, err)
+		return
 	}
 
+	limit, cursor, err := parsePage(r)
 	if err != nil {
-		respondWithError(w, http.StatusInternalServerError, "Couldn't retrieve chirps", err)
+		respondWithError(w, http.StatusBadRequest, err.Error(), err)
 		return
 	}
 
 	// Filter by author if specified
-	chirps := []Chirp{}
+	authorID := uuid.NullUUID{}
 	if authorIDStr != "" {
-		authorID, err := uuid.Parse(authorIDStr)
+		id, err := uuid.Parse(authorIDStr)
 		if err != nil {
 			respondWithError(w, http.StatusBadRequest, "Invalid author ID format", err)
 			return
 		}
+		authorID = uuid.NullUUID{UUID: id, Valid: true}
+	}
 
-		// Only include chirps from specified author
-		for _, dbChirp := range dbChirps {
-			if dbChirp.UserID == authorID {
-				chirps = append(chirps, Chirp{
-					ID:        dbChirp.ID,
-					CreatedAt: dbChirp.CreatedAt,
-					UpdatedAt: dbChirp.UpdatedAt,
-					UserID:    dbChirp.UserID,
-					Body:      dbChirp.Body,
-				})
-			}
-		}
+	cursorCreatedAt, cursorID := cursorPosition(cursor)
+
+	// Walking back to a previous page means reading against the sort order
+	ascending := sortOrder == "asc"
+	if walkingBack(cursor) {
+		ascending = !ascending
+	}
+
+	// Fetch one extra row so we know whether another page exists
+	var dbChirps []database.Chirp
+	if ascending {
+		dbChirps, err = cfg.db.ListChirpsAsc(r.Context(), database.ListChirpsAscParams{
+			AuthorID:        authorID,
+			CursorCreatedAt: cursorCreatedAt,
+			CursorID:        cursorID,
+			ViewerID:        viewerID,
+			RowLimit:        int32(limit + 1),
+		})
 	} else {
-		// Include all chirps
-		for _, dbChirp := range dbChirps {
-			chirps = append(chirps, Chirp{
-				ID:        dbChirp.ID,
-				CreatedAt: dbChirp.CreatedAt,
-				UpdatedAt: dbChirp.UpdatedAt,
-				UserID:    dbChirp.UserID,
-				Body:      dbChirp.Body,
-			})
-		}
+		dbChirps, err = cfg.db.ListChirpsDesc(r.Context(), database.ListChirpsDescParams{
+			AuthorID:        authorID,
+			CursorCreatedAt: cursorCreatedAt,
+			CursorID:        cursorID,
+			ViewerID:        viewerID,
+			RowLimit:        int32(limit + 1),
+		})
 	}
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't retrieve chirps", err)
+		return
+	}
+
+	dbChirps, nextCursor, prevCursor := pagination.Paginate(dbChirps, limit, cursor, func(c database.Chirp) pagination.Cursor {
+		return pagination.Cursor{CreatedAt: c.CreatedAt, ID: c.ID}
+	})
 
-	respondWithJSON(w, http.StatusOK, chirps)
+	chirps, err := cfg.chirpsForViewer(r.Context(), viewerID, dbChirps)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't retrieve chirps", err)
+		return
+	}
+
+	setLinkHeader(w, r, nextCursor, prevCursor)
+	respondWithJSON(w, http.StatusOK, response{
+		Chirps:     chirps,
+		NextCursor: nextCursor,
+		PrevCursor: prevCursor,
+	})
 }
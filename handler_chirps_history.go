@@ -0,0 +1,74 @@
+package main
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/srinivassivaratri/Chirpy/internal/database"
+)
+
+// ChirpRevision is a body a chirp had before it was edited
+type ChirpRevision struct {
+	Version    int32     `json:"version"`
+	Body       string    `json:"body"`
+	CreatedAt  time.Time `json:"created_at"`
+	ReplacedAt time.Time `json:"replaced_at"`
+}
+
+func (cfg *apiConfig) handlerChirpsHistory(w http.ResponseWriter, r *http.Request) {
+	type response struct {
+		Chirp     Chirp           `json:"chirp"`
+		Revisions []ChirpRevision `json:"revisions"`
+	}
+
+	chirpIDString := r.PathValue("chirpID")
+	chirpID, err := uuid.Parse(chirpIDString)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid chirp ID", err)
+		return
+	}
+
+	viewerID, err := cfg.viewerID(r)
+	if err != nil {
+		respondWithAuthError(w, err)
+		return
+	}
+
+	dbChirp, err := cfg.db.GetChirp(r.Context(), chirpID)
+	if err != nil {
+		respondWithError(w, http.StatusNotFound, "Couldn't get chirp", err)
+		return
+	}
+	if cfg.respondIfHidden(w, r, viewerID, dbChirp) {
+		return
+	}
+
+	chirps, err := cfg.chirpsForViewer(r.Context(), viewerID, []database.Chirp{dbChirp})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't get chirp", err)
+		return
+	}
+
+	dbRevisions, err := cfg.db.ListChirpRevisions(r.Context(), chirpID)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't get chirp history", err)
+		return
+	}
+
+	// Newest first, so revisions[0] is what the chirp said just before its current version
+	revisions := []ChirpRevision{}
+	for _, dbRevision := range dbRevisions {
+		revisions = append(revisions, ChirpRevision{
+			Version:    dbRevision.Version,
+			Body:       dbRevision.Body,
+			CreatedAt:  dbRevision.CreatedAt,
+			ReplacedAt: dbRevision.ReplacedAt,
+		})
+	}
+
+	respondWithJSON(w, http.StatusOK, response{
+		Chirp:     chirps[0],
+		Revisions: revisions,
+	})
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/srinivassivaratri/Chirpy/internal/auth"
+	"github.com/srinivassivaratri/Chirpy/internal/database"
+	"github.com/srinivassivaratri/Chirpy/internal/notifications"
+)
+
+func (cfg *apiConfig) handlerChirpsLike(w http.ResponseWriter, r *http.Request) {
+	chirpIDString := r.PathValue("chirpID")
+	chirpID, err := uuid.Parse(chirpIDString)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid chirp ID", err)
+		return
+	}
+
+	token, err := auth.GetBearerToken(r.Header)
+	if err != nil {
+		respondWithAuthError(w, err)
+		return
+	}
+	userID, err := cfg.validateJWT(r.Context(), token)
+	if err != nil {
+		respondWithAuthError(w, err)
+		return
+	}
+
+	chirp, err := cfg.db.GetChirp(r.Context(), chirpID)
+	if errors.Is(err, sql.ErrNoRows) {
+		respondWithError(w, http.StatusNotFound, "Couldn't get chirp", err)
+		return
+	}
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't get chirp", err)
+		return
+	}
+	// Chirps a moderator has hidden can't be liked
+	if chirp.HiddenAt.Valid {
+		respondWithError(w, http.StatusNotFound, "Couldn't get chirp", nil)
+		return
+	}
+
+	// Liking a chirp twice is a no-op
+	liked, err := cfg.db.LikeChirp(r.Context(), database.LikeChirpParams{
+		ChirpID: chirpID,
+		UserID:  userID,
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't like chirp", err)
+		return
+	}
+
+	if liked > 0 {
+		cfg.notifier.Notify(notifications.Notification{
+			UserID:  chirp.UserID,
+			ActorID: uuid.NullUUID{UUID: userID, Valid: true},
+			Kind:    notifications.KindLike,
+			ChirpID: uuid.NullUUID{UUID: chirpID, Valid: true},
+		})
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (cfg *apiConfig) handlerChirpsUnlike(w http.ResponseWriter, r *http.Request) {
+	chirpIDString := r.PathValue("chirpID")
+	chirpID, err := uuid.Parse(chirpIDString)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid chirp ID", err)
+		return
+	}
+
+	token, err := auth.GetBearerToken(r.Header)
+	if err != nil {
+		respondWithAuthError(w, err)
+		return
+	}
+	userID, err := cfg.validateJWT(r.Context(), token)
+	if err != nil {
+		respondWithAuthError(w, err)
+		return
+	}
+
+	err = cfg.db.UnlikeChirp(r.Context(), database.UnlikeChirpParams{
+		ChirpID: chirpID,
+		UserID:  userID,
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't unlike chirp", err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,139 @@
+package main
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/srinivassivaratri/Chirpy/internal/auth"
+	"github.com/srinivassivaratri/Chirpy/internal/database"
+	"github.com/srinivassivaratri/Chirpy/internal/filter"
+)
+
+// handlerChirpsRechirp shares a chirp. Without a body it's a plain rechirp,
+// with one it's a quote carrying the user's own commentary.
+func (cfg *apiConfig) handlerChirpsRechirp(w http.ResponseWriter, r *http.Request) {
+	type parameters struct {
+		Body string `json:"body"`
+	}
+
+	chirpIDString := r.PathValue("chirpID")
+	chirpID, err := uuid.Parse(chirpIDString)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid chirp ID", err)
+		return
+	}
+
+	token, err := auth.GetBearerToken(r.Header)
+	if err != nil {
+		respondWithAuthError(w, err)
+		return
+	}
+	userID, err := cfg.validateJWT(r.Context(), token)
+	if err != nil {
+		respondWithAuthError(w, err)
+		return
+	}
+
+	// The request body is optional for plain rechirps
+	decoder := json.NewDecoder(r.Body)
+	params := parameters{}
+	err = decoder.Decode(&params)
+	if err != nil && !errors.Is(err, io.EOF) {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't decode parameters", err)
+		return
+	}
+
+	original, err := cfg.db.GetChirp(r.Context(), chirpID)
+	if errors.Is(err, sql.ErrNoRows) {
+		respondWithError(w, http.StatusNotFound, "Couldn't get chirp", err)
+		return
+	}
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't get chirp", err)
+		return
+	}
+	// Rechirping a plain rechirp shares the chirp it points at
+	if !original.HiddenAt.Valid && original.RechirpOf.Valid && original.Body == "" {
+		original, err = cfg.db.GetChirp(r.Context(), original.RechirpOf.UUID)
+		if errors.Is(err, sql.ErrNoRows) {
+			respondWithError(w, http.StatusNotFound, "Couldn't get chirp", err)
+			return
+		}
+		if err != nil {
+			respondWithError(w, http.StatusInternalServerError, "Couldn't get chirp", err)
+			return
+		}
+	}
+	// Chirps a moderator has hidden can't be shared
+	if original.HiddenAt.Valid {
+		respondWithError(w, http.StatusNotFound, "Couldn't get chirp", nil)
+		return
+	}
+	rechirpOf := uuid.NullUUID{UUID: original.ID, Valid: true}
+
+	filtered := filter.Result{}
+	if params.Body != "" {
+		filtered, err = cfg.validateChirp(params.Body)
+		if err != nil {
+			respondWithError(w, http.StatusBadRequest, err.Error(), err)
+			return
+		}
+	} else {
+		// Each user can only plainly rechirp a chirp once
+		if cfg.respondWithExistingRechirp(w, r, userID, rechirpOf) {
+			return
+		}
+	}
+
+	chirp, err := cfg.createChirp(r.Context(), database.CreateChirpParams{
+		UserID:    userID,
+		Body:      filtered.Text,
+		RechirpOf: rechirpOf,
+	}, nil, nil)
+	if params.Body == "" && isUniqueViolation(err) {
+		// Lost a race with the same plain rechirp made at the same time
+		if !cfg.respondWithExistingRechirp(w, r, userID, rechirpOf) {
+			respondWithError(w, http.StatusInternalServerError, "Couldn't get rechirp", err)
+		}
+		return
+	}
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't create rechirp", err)
+		return
+	}
+	cfg.flagForReview(r.Context(), uuid.NullUUID{UUID: chirp.ID, Valid: true}, userID, filtered.Flagged)
+
+	cfg.respondWithRechirp(w, r, userID, chirp, http.StatusCreated)
+}
+
+// respondWithExistingRechirp responds with the user's plain rechirp of a
+// chirp and returns true, or returns false if they haven't made one. Errors
+// are responded to as well.
+func (cfg *apiConfig) respondWithExistingRechirp(w http.ResponseWriter, r *http.Request, userID uuid.UUID, rechirpOf uuid.NullUUID) bool {
+	existing, err := cfg.db.GetPlainRechirp(r.Context(), database.GetPlainRechirpParams{
+		UserID:    userID,
+		RechirpOf: rechirpOf,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		return false
+	}
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't get rechirp", err)
+		return true
+	}
+	cfg.respondWithRechirp(w, r, userID, existing, http.StatusOK)
+	return true
+}
+
+func (cfg *apiConfig) respondWithRechirp(w http.ResponseWriter, r *http.Request, userID uuid.UUID, dbChirp database.Chirp, code int) {
+	chirps, err := cfg.chirpsForViewer(r.Context(), uuid.NullUUID{UUID: userID, Valid: true}, []database.Chirp{dbChirp})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't get rechirp", err)
+		return
+	}
+	respondWithJSON(w, code, chirps[0])
+}
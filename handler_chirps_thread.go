@@ -0,0 +1,179 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/srinivassivaratri/Chirpy/internal/database"
+	"github.com/srinivassivaratri/Chirpy/internal/pagination"
+)
+
+type threadReply struct {
+	Chirp
+	Replies []*threadReply `json:"replies"`
+}
+
+func (cfg *apiConfig) handlerChirpsThread(w http.ResponseWriter, r *http.Request) {
+	type response struct {
+		Ancestors  []interface{}  `json:"ancestors"`
+		Chirp      Chirp          `json:"chirp"`
+		Replies    []*threadReply `json:"replies"`
+		NextCursor string         `json:"next_cursor,omitempty"`
+		PrevCursor string         `json:"prev_cursor,omitempty"`
+	}
+
+	chirpIDString := r.PathValue("chirpID")
+	chirpID, err := uuid.Parse(chirpIDString)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid chirp ID", err)
+		return
+	}
+
+	viewerID, err := cfg.viewerID(r)
+	if err != nil {
+		respondWithAuthError(w, err)
+		return
+	}
+
+	limit, cursor, err := parsePage(r)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error(), err)
+		return
+	}
+
+	dbChirp, err := cfg.db.GetChirp(r.Context(), chirpID)
+	if errors.Is(err, sql.ErrNoRows) {
+		respondWithError(w, http.StatusNotFound, "Couldn't get chirp", err)
+		return
+	}
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't get chirp", err)
+		return
+	}
+	if cfg.respondIfHidden(w, r, viewerID, dbChirp) {
+		return
+	}
+
+	chirps, err := cfg.chirpsForViewer(r.Context(), viewerID, []database.Chirp{dbChirp})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't get chirp", err)
+		return
+	}
+
+	ancestors, err := cfg.getChirpAncestors(r.Context(), viewerID, dbChirp)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't get thread", err)
+		return
+	}
+
+	cursorCreatedAt, cursorID := cursorPosition(cursor)
+
+	// Replies from anyone the viewer has blocked or muted are left out, like
+	// everywhere else. Replies read oldest first, so walking back to a previous page reads newest first
+	var dbReplies []database.Chirp
+	if walkingBack(cursor) {
+		dbReplies, err = cfg.db.ListChirpRepliesDesc(r.Context(), database.ListChirpRepliesDescParams{
+			ChirpID:         chirpID,
+			CursorCreatedAt: cursorCreatedAt,
+			CursorID:        cursorID,
+			ViewerID:        viewerID,
+			RowLimit:        int32(limit + 1),
+		})
+	} else {
+		dbReplies, err = cfg.db.ListChirpRepliesAsc(r.Context(), database.ListChirpRepliesAscParams{
+			ChirpID:         chirpID,
+			CursorCreatedAt: cursorCreatedAt,
+			CursorID:        cursorID,
+			ViewerID:        viewerID,
+			RowLimit:        int32(limit + 1),
+		})
+	}
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't get replies", err)
+		return
+	}
+
+	dbReplies, nextCursor, prevCursor := pagination.Paginate(dbReplies, limit, cursor, func(c database.Chirp) pagination.Cursor {
+		return pagination.Cursor{CreatedAt: c.CreatedAt, ID: c.ID}
+	})
+
+	replies, err := cfg.chirpsForViewer(r.Context(), viewerID, dbReplies)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't get replies", err)
+		return
+	}
+
+	setLinkHeader(w, r, nextCursor, prevCursor)
+	respondWithJSON(w, http.StatusOK, response{
+		Ancestors:  ancestors,
+		Chirp:      chirps[0],
+		Replies:    buildReplyTree(replies),
+		NextCursor: nextCursor,
+		PrevCursor: prevCursor,
+	})
+}
+
+// getChirpAncestors returns the chain of chirps above dbChirp, root first.
+// Ancestors that have been deleted or hidden come back as tombstones.
+func (cfg *apiConfig) getChirpAncestors(ctx context.Context, viewerID uuid.NullUUID, dbChirp database.Chirp) ([]interface{}, error) {
+	ancestors := []interface{}{}
+	if len(dbChirp.AncestorIds) == 0 {
+		return ancestors, nil
+	}
+
+	dbAncestors, err := cfg.db.GetChirpsByIDs(ctx, dbChirp.AncestorIds)
+	if err != nil {
+		return nil, err
+	}
+	chirps, err := cfg.chirpsForViewer(ctx, viewerID, dbAncestors)
+	if err != nil {
+		return nil, err
+	}
+	found := map[uuid.UUID]Chirp{}
+	for _, chirp := range chirps {
+		found[chirp.ID] = chirp
+	}
+
+	for _, id := range dbChirp.AncestorIds {
+		chirp, ok := found[id]
+		if ok && chirp.Hidden {
+			ancestors = append(ancestors, ChirpTombstone{ID: id, Hidden: true})
+			continue
+		}
+		if ok {
+			ancestors = append(ancestors, chirp)
+			continue
+		}
+		ancestors = append(ancestors, ChirpTombstone{ID: id, Deleted: true})
+	}
+	return ancestors, nil
+}
+
+// buildReplyTree nests one page of replies under their parents. Parents are
+// always older than their replies, so a reply whose parent isn't on this page
+// either answers a chirp on an earlier page or one that was deleted. Those
+// start a new branch at the top of the tree and the client can place them
+// using in_reply_to.
+func buildReplyTree(replies []Chirp) []*threadReply {
+	tree := []*threadReply{}
+	nodes := map[uuid.UUID]*threadReply{}
+	for _, reply := range replies {
+		node := &threadReply{
+			Chirp:   reply,
+			Replies: []*threadReply{},
+		}
+		nodes[reply.ID] = node
+
+		if reply.InReplyTo != nil {
+			if parent, ok := nodes[*reply.InReplyTo]; ok {
+				parent.Replies = append(parent.Replies, node)
+				continue
+			}
+		}
+		tree = append(tree, node)
+	}
+	return tree
+}
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/srinivassivaratri/Chirpy/internal/auth"
+	"github.com/srinivassivaratri/Chirpy/internal/database"
+)
+
+// Users without Chirpy Red can only edit a chirp this long after posting it
+const chirpEditWindow = 15 * time.Minute
+
+func (cfg *apiConfig) handlerChirpsUpdate(w http.ResponseWriter, r *http.Request) {
+	type parameters struct {
+		Body string `json:"body"`
+	}
+
+	chirpIDString := r.PathValue("chirpID")
+	chirpID, err := uuid.Parse(chirpIDString)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid chirp ID", err)
+		return
+	}
+
+	token, err := auth.GetBearerToken(r.Header)
+	if err != nil {
+		respondWithAuthError(w, err)
+		return
+	}
+	userID, err := cfg.validateJWT(r.Context(), token)
+	if err != nil {
+		respondWithAuthError(w, err)
+		return
+	}
+
+	decoder := json.NewDecoder(r.Body)
+	params := parameters{}
+	err = decoder.Decode(&params)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't decode parameters", err)
+		return
+	}
+
+	dbChirp, err := cfg.db.GetChirp(r.Context(), chirpID)
+	if err != nil {
+		respondWithError(w, http.StatusNotFound, "Couldn't get chirp", err)
+		return
+	}
+	if dbChirp.UserID != userID {
+		respondWithError(w, http.StatusForbidden, "You can't edit this chirp", err)
+		return
+	}
+	if dbChirp.HiddenAt.Valid {
+		respondWithError(w, http.StatusUnavailableForLegalReasons, "This chirp was hidden by a moderator", nil)
+		return
+	}
+	if dbChirp.RechirpOf.Valid && dbChirp.Body == "" {
+		respondWithError(w, http.StatusBadRequest, "Plain rechirps can't be edited", nil)
+		return
+	}
+
+	user, err := cfg.db.GetUser(r.Context(), userID)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't get user", err)
+		return
+	}
+	if !user.IsChirpyRed && time.Since(dbChirp.CreatedAt) > chirpEditWindow {
+		respondWithError(w, http.StatusForbidden, "Chirps can only be edited for 15 minutes without Chirpy Red", nil)
+		return
+	}
+
+	// An empty body is what makes a rechirp plain, so a quote has to keep one
+	if dbChirp.RechirpOf.Valid && strings.TrimSpace(params.Body) == "" {
+		respondWithError(w, http.StatusBadRequest, "Quotes can't be empty", nil)
+		return
+	}
+	filtered, err := cfg.validateChirp(params.Body)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error(), err)
+		return
+	}
+
+	// The old body is saved as a revision in the same statement, and any
+	// hashtags are swapped for the ones in the new body
+	chirp, err := cfg.updateChirpBody(r.Context(), database.UpdateChirpBodyParams{
+		ID:   chirpID,
+		Body: filtered.Text,
+	})
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+			respondWithError(w, http.StatusConflict, "Chirp was edited at the same time, try again", err)
+			return
+		}
+		respondWithError(w, http.StatusInternalServerError, "Couldn't edit chirp", err)
+		return
+	}
+	cfg.flagForReview(r.Context(), uuid.NullUUID{UUID: chirp.ID, Valid: true}, userID, filtered.Flagged)
+
+	chirps, err := cfg.chirpsForViewer(r.Context(), uuid.NullUUID{UUID: userID, Valid: true}, []database.Chirp{chirp})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't get chirp", err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, chirps[0])
+}
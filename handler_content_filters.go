@@ -0,0 +1,180 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/srinivassivaratri/Chirpy/internal/database"
+	"github.com/srinivassivaratri/Chirpy/internal/filter"
+)
+
+// Most words one list in the database can have
+const maxContentFilterWords = 1000
+
+// ContentFilter is a word list and what happens to text that uses its words
+type ContentFilter struct {
+	Name   string   `json:"name"`
+	Action string   `json:"action"`
+	Words  []string `json:"words"`
+}
+
+func filterToContentFilter(f filter.Filter) ContentFilter {
+	contentFilter := ContentFilter{
+		Name:   f.Name(),
+		Action: string(f.Action()),
+		Words:  []string{},
+	}
+	if wordList, ok := f.(*filter.WordList); ok {
+		contentFilter.Words = wordList.Words()
+	}
+	return contentFilter
+}
+
+// handlerContentFiltersGet lists the filters in use, from the filters file
+// and the database
+func (cfg *apiConfig) handlerContentFiltersGet(w http.ResponseWriter, r *http.Request) {
+	cfg.respondWithContentFilters(w, http.StatusOK)
+}
+
+func (cfg *apiConfig) respondWithContentFilters(w http.ResponseWriter, code int) {
+	type response struct {
+		Filters []ContentFilter `json:"filters"`
+	}
+
+	filters := []ContentFilter{}
+	for _, f := range cfg.contentFilter.Filters() {
+		filters = append(filters, filterToContentFilter(f))
+	}
+	respondWithJSON(w, code, response{
+		Filters: filters,
+	})
+}
+
+// handlerContentFiltersPut creates a word list in the database, or replaces
+// the action and words of one that's already there
+func (cfg *apiConfig) handlerContentFiltersPut(w http.ResponseWriter, r *http.Request) {
+	type parameters struct {
+		Action string   `json:"action"`
+		Words  []string `json:"words"`
+	}
+
+	name := r.PathValue("name")
+	if !filter.ValidName(name) {
+		respondWithError(w, http.StatusBadRequest, "Filter names are 1 to 32 lowercase letters, numbers, underscores or dashes", nil)
+		return
+	}
+
+	decoder := json.NewDecoder(r.Body)
+	params := parameters{}
+	err := decoder.Decode(&params)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't decode parameters", err)
+		return
+	}
+	if !filter.ValidAction(params.Action) {
+		respondWithError(w, http.StatusBadRequest, "Action must be mask, reject or flag", nil)
+		return
+	}
+	if len(params.Words) > maxContentFilterWords {
+		msg := fmt.Sprintf("A filter can have at most %d words", maxContentFilterWords)
+		respondWithError(w, http.StatusBadRequest, msg, nil)
+		return
+	}
+	for _, word := range params.Words {
+		if !filter.ValidWord(word) {
+			msg := fmt.Sprintf("%q isn't a single word", word)
+			respondWithError(w, http.StatusBadRequest, msg, nil)
+			return
+		}
+	}
+
+	err = cfg.saveContentFilter(r.Context(), name, params.Action, params.Words)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't save filter", err)
+		return
+	}
+
+	err = cfg.reloadFilters(r.Context())
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't reload filters", err)
+		return
+	}
+	cfg.publishFiltersChanged(r.Context())
+
+	respondWithJSON(w, http.StatusOK, filterToContentFilter(filter.NewWordList(name, filter.Action(params.Action), params.Words)))
+}
+
+// saveContentFilter replaces a word list's action and words
+func (cfg *apiConfig) saveContentFilter(ctx context.Context, name, action string, words []string) error {
+	tx, err := cfg.dbConn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	qtx := cfg.db.WithTx(tx)
+
+	_, err = qtx.UpsertContentFilter(ctx, database.UpsertContentFilterParams{
+		Name:   name,
+		Action: action,
+	})
+	if err != nil {
+		return err
+	}
+	err = qtx.DeleteContentFilterWords(ctx, name)
+	if err != nil {
+		return err
+	}
+	// Stored folded, the way they're matched
+	folded := []string{}
+	for _, word := range words {
+		folded = append(folded, filter.Fold(word))
+	}
+	err = qtx.AddContentFilterWords(ctx, database.AddContentFilterWordsParams{
+		FilterName: name,
+		Words:      folded,
+	})
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// handlerContentFiltersDelete deletes a word list from the database. Lists
+// from the filters file have to be taken out of the file.
+func (cfg *apiConfig) handlerContentFiltersDelete(w http.ResponseWriter, r *http.Request) {
+	name := r.PathValue("name")
+
+	deleted, err := cfg.db.DeleteContentFilter(r.Context(), name)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't delete filter", err)
+		return
+	}
+	if deleted == 0 {
+		respondWithError(w, http.StatusNotFound, "Couldn't find filter", nil)
+		return
+	}
+
+	err = cfg.reloadFilters(r.Context())
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't reload filters", err)
+		return
+	}
+	cfg.publishFiltersChanged(r.Context())
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handlerContentFiltersReload reloads the filters file and the database
+// lists on every instance
+func (cfg *apiConfig) handlerContentFiltersReload(w http.ResponseWriter, r *http.Request) {
+	err := cfg.reloadFilters(r.Context())
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't reload filters", err)
+		return
+	}
+	cfg.publishFiltersChanged(r.Context())
+
+	cfg.respondWithContentFilters(w, http.StatusOK)
+}
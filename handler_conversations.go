@@ -0,0 +1,252 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/srinivassivaratri/Chirpy/internal/auth"
+	"github.com/srinivassivaratri/Chirpy/internal/database"
+	"github.com/srinivassivaratri/Chirpy/internal/pagination"
+)
+
+func (cfg *apiConfig) handlerConversationsCreate(w http.ResponseWriter, r *http.Request) {
+	type parameters struct {
+		UserIDs []uuid.UUID `json:"user_ids"`
+	}
+
+	token, err := auth.GetBearerToken(r.Header)
+	if err != nil {
+		respondWithAuthError(w, err)
+		return
+	}
+	userID, err := cfg.validateJWT(r.Context(), token)
+	if err != nil {
+		respondWithAuthError(w, err)
+		return
+	}
+
+	decoder := json.NewDecoder(r.Body)
+	params := parameters{}
+	err = decoder.Decode(&params)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't decode parameters", err)
+		return
+	}
+
+	recipientIDs := []uuid.UUID{}
+	seen := map[uuid.UUID]bool{userID: true}
+	for _, id := range params.UserIDs {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		recipientIDs = append(recipientIDs, id)
+	}
+	if len(recipientIDs) == 0 {
+		respondWithError(w, http.StatusBadRequest, "A conversation needs someone else in it", nil)
+		return
+	}
+	if len(recipientIDs) >= maxConversationParticipants {
+		msg := fmt.Sprintf("A conversation can have at most %d people", maxConversationParticipants)
+		respondWithError(w, http.StatusBadRequest, msg, nil)
+		return
+	}
+
+	// Messaging someone you already have a conversation with picks it back up
+	dmKey := sql.NullString{}
+	if len(recipientIDs) == 1 {
+		dmKey = sql.NullString{String: directConversationKey(userID, recipientIDs[0]), Valid: true}
+		existing, err := cfg.db.GetDirectConversation(r.Context(), dmKey)
+		if err == nil {
+			cfg.respondWithConversation(w, r, http.StatusOK, userID, existing)
+			return
+		}
+		if !errors.Is(err, sql.ErrNoRows) {
+			respondWithError(w, http.StatusInternalServerError, "Couldn't get conversation", err)
+			return
+		}
+	}
+
+	for _, recipientID := range recipientIDs {
+		recipient, err := cfg.db.GetUser(r.Context(), recipientID)
+		if errors.Is(err, sql.ErrNoRows) {
+			respondWithError(w, http.StatusNotFound, "Couldn't find user", err)
+			return
+		}
+		if err != nil {
+			respondWithError(w, http.StatusInternalServerError, "Couldn't get user", err)
+			return
+		}
+		blocked, err := cfg.db.IsBlockedEitherWay(r.Context(), database.IsBlockedEitherWayParams{
+			UserID:  userID,
+			OtherID: recipientID,
+		})
+		if err != nil {
+			respondWithError(w, http.StatusInternalServerError, "Couldn't check blocks", err)
+			return
+		}
+		if blocked {
+			respondWithError(w, http.StatusForbidden, "User doesn't accept messages from you", nil)
+			return
+		}
+		allowed, err := cfg.canMessage(r.Context(), userID, recipient)
+		if err != nil {
+			respondWithError(w, http.StatusInternalServerError, "Couldn't check DM policy", err)
+			return
+		}
+		if !allowed {
+			respondWithError(w, http.StatusForbidden, "User doesn't accept messages from you", nil)
+			return
+		}
+	}
+
+	conversation, err := cfg.createConversation(r.Context(), dmKey, append(recipientIDs, userID))
+	if isUniqueViolation(err) {
+		// Lost a race with the other user starting the same conversation
+		conversation, err = cfg.db.GetDirectConversation(r.Context(), dmKey)
+		if err != nil {
+			respondWithError(w, http.StatusInternalServerError, "Couldn't get conversation", err)
+			return
+		}
+		cfg.respondWithConversation(w, r, http.StatusOK, userID, conversation)
+		return
+	}
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't create conversation", err)
+		return
+	}
+
+	cfg.respondWithConversation(w, r, http.StatusCreated, userID, conversation)
+}
+
+func (cfg *apiConfig) createConversation(ctx context.Context, dmKey sql.NullString, userIDs []uuid.UUID) (database.Conversation, error) {
+	tx, err := cfg.dbConn.BeginTx(ctx, nil)
+	if err != nil {
+		return database.Conversation{}, err
+	}
+	defer tx.Rollback()
+	qtx := cfg.db.WithTx(tx)
+
+	conversation, err := qtx.CreateConversation(ctx, dmKey)
+	if err != nil {
+		return database.Conversation{}, err
+	}
+	err = qtx.AddConversationParticipants(ctx, database.AddConversationParticipantsParams{
+		ConversationID: conversation.ID,
+		UserIds:        userIDs,
+	})
+	if err != nil {
+		return database.Conversation{}, err
+	}
+	err = tx.Commit()
+	if err != nil {
+		return database.Conversation{}, err
+	}
+	return conversation, nil
+}
+
+func (cfg *apiConfig) respondWithConversation(w http.ResponseWriter, r *http.Request, code int, userID uuid.UUID, dbConversation database.Conversation) {
+	unreadCount, err := cfg.db.CountUnreadMessages(r.Context(), database.CountUnreadMessagesParams{
+		ConversationID: dbConversation.ID,
+		UserID:         userID,
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't count unread messages", err)
+		return
+	}
+	conversations, err := cfg.conversationsForUser(r.Context(), []database.Conversation{dbConversation}, []int64{unreadCount})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't get conversation", err)
+		return
+	}
+	respondWithJSON(w, code, conversations[0])
+}
+
+func (cfg *apiConfig) handlerConversationsGet(w http.ResponseWriter, r *http.Request) {
+	type response struct {
+		Conversations []Conversation `json:"conversations"`
+		NextCursor    string         `json:"next_cursor,omitempty"`
+		PrevCursor    string         `json:"prev_cursor,omitempty"`
+	}
+
+	token, err := auth.GetBearerToken(r.Header)
+	if err != nil {
+		respondWithAuthError(w, err)
+		return
+	}
+	userID, err := cfg.validateJWT(r.Context(), token)
+	if err != nil {
+		respondWithAuthError(w, err)
+		return
+	}
+
+	limit, cursor, err := parsePage(r)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error(), err)
+		return
+	}
+	cursorUpdatedAt, cursorID := cursorPosition(cursor)
+
+	// Most recently active first. A conversation that gets a new message
+	// while someone is paging moves to the top, so it can show up twice.
+	var rows []database.ListConversationsDescRow
+	if walkingBack(cursor) {
+		ascRows, err := cfg.db.ListConversationsAsc(r.Context(), database.ListConversationsAscParams{
+			UserID:          userID,
+			CursorUpdatedAt: cursorUpdatedAt,
+			CursorID:        cursorID,
+			RowLimit:        int32(limit + 1),
+		})
+		if err != nil {
+			respondWithError(w, http.StatusInternalServerError, "Couldn't retrieve conversations", err)
+			return
+		}
+		for _, row := range ascRows {
+			rows = append(rows, database.ListConversationsDescRow(row))
+		}
+	} else {
+		rows, err = cfg.db.ListConversationsDesc(r.Context(), database.ListConversationsDescParams{
+			UserID:          userID,
+			CursorUpdatedAt: cursorUpdatedAt,
+			CursorID:        cursorID,
+			RowLimit:        int32(limit + 1),
+		})
+		if err != nil {
+			respondWithError(w, http.StatusInternalServerError, "Couldn't retrieve conversations", err)
+			return
+		}
+	}
+
+	rows, nextCursor, prevCursor := pagination.Paginate(rows, limit, cursor, func(row database.ListConversationsDescRow) pagination.Cursor {
+		return pagination.Cursor{CreatedAt: row.UpdatedAt, ID: row.ID}
+	})
+
+	dbConversations := []database.Conversation{}
+	unreadCounts := []int64{}
+	for _, row := range rows {
+		dbConversations = append(dbConversations, database.Conversation{
+			ID:        row.ID,
+			CreatedAt: row.CreatedAt,
+			UpdatedAt: row.UpdatedAt,
+			DmKey:     row.DmKey,
+		})
+		unreadCounts = append(unreadCounts, row.UnreadCount)
+	}
+	conversations, err := cfg.conversationsForUser(r.Context(), dbConversations, unreadCounts)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't retrieve conversations", err)
+		return
+	}
+
+	setLinkHeader(w, r, nextCursor, prevCursor)
+	respondWithJSON(w, http.StatusOK, response{
+		Conversations: conversations,
+		NextCursor:    nextCursor,
+		PrevCursor:    prevCursor,
+	})
+}
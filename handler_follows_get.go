@@ -0,0 +1,136 @@
+package main
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/srinivassivaratri/Chirpy/internal/database"
+	"github.com/srinivassivaratri/Chirpy/internal/pagination"
+)
+
+// Follow is one entry in a followers or following list
+type Follow struct {
+	UserID     uuid.UUID `json:"user_id"`
+	FollowedAt time.Time `json:"followed_at"`
+}
+
+type followsResponse struct {
+	Users      []Follow `json:"users"`
+	NextCursor string   `json:"next_cursor,omitempty"`
+	PrevCursor string   `json:"prev_cursor,omitempty"`
+}
+
+func (cfg *apiConfig) handlerFollowersGet(w http.ResponseWriter, r *http.Request) {
+	userIDString := r.PathValue("userID")
+	userID, err := uuid.Parse(userIDString)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid user ID", err)
+		return
+	}
+
+	limit, cursor, err := parsePage(r)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error(), err)
+		return
+	}
+	cursorCreatedAt, cursorID := cursorPosition(cursor)
+
+	// Newest followers first
+	var dbFollows []database.Follow
+	if walkingBack(cursor) {
+		dbFollows, err = cfg.db.ListFollowersAsc(r.Context(), database.ListFollowersAscParams{
+			UserID:          userID,
+			CursorCreatedAt: cursorCreatedAt,
+			CursorID:        cursorID,
+			RowLimit:        int32(limit + 1),
+		})
+	} else {
+		dbFollows, err = cfg.db.ListFollowersDesc(r.Context(), database.ListFollowersDescParams{
+			UserID:          userID,
+			CursorCreatedAt: cursorCreatedAt,
+			CursorID:        cursorID,
+			RowLimit:        int32(limit + 1),
+		})
+	}
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't get followers", err)
+		return
+	}
+
+	dbFollows, nextCursor, prevCursor := pagination.Paginate(dbFollows, limit, cursor, func(f database.Follow) pagination.Cursor {
+		return pagination.Cursor{CreatedAt: f.CreatedAt, ID: f.FollowerID}
+	})
+
+	users := []Follow{}
+	for _, dbFollow := range dbFollows {
+		users = append(users, Follow{
+			UserID:     dbFollow.FollowerID,
+			FollowedAt: dbFollow.CreatedAt,
+		})
+	}
+
+	setLinkHeader(w, r, nextCursor, prevCursor)
+	respondWithJSON(w, http.StatusOK, followsResponse{
+		Users:      users,
+		NextCursor: nextCursor,
+		PrevCursor: prevCursor,
+	})
+}
+
+func (cfg *apiConfig) handlerFollowingGet(w http.ResponseWriter, r *http.Request) {
+	userIDString := r.PathValue("userID")
+	userID, err := uuid.Parse(userIDString)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid user ID", err)
+		return
+	}
+
+	limit, cursor, err := parsePage(r)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error(), err)
+		return
+	}
+	cursorCreatedAt, cursorID := cursorPosition(cursor)
+
+	// Most recently followed first
+	var dbFollows []database.Follow
+	if walkingBack(cursor) {
+		dbFollows, err = cfg.db.ListFollowingAsc(r.Context(), database.ListFollowingAscParams{
+			UserID:          userID,
+			CursorCreatedAt: cursorCreatedAt,
+			CursorID:        cursorID,
+			RowLimit:        int32(limit + 1),
+		})
+	} else {
+		dbFollows, err = cfg.db.ListFollowingDesc(r.Context(), database.ListFollowingDescParams{
+			UserID:          userID,
+			CursorCreatedAt: cursorCreatedAt,
+			CursorID:        cursorID,
+			RowLimit:        int32(limit + 1),
+		})
+	}
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't get followed users", err)
+		return
+	}
+
+	dbFollows, nextCursor, prevCursor := pagination.Paginate(dbFollows, limit, cursor, func(f database.Follow) pagination.Cursor {
+		return pagination.Cursor{CreatedAt: f.CreatedAt, ID: f.FolloweeID}
+	})
+
+	users := []Follow{}
+	for _, dbFollow := range dbFollows {
+		users = append(users, Follow{
+			UserID:     dbFollow.FolloweeID,
+			FollowedAt: dbFollow.CreatedAt,
+		})
+	}
+
+	setLinkHeader(w, r, nextCursor, prevCursor)
+	respondWithJSON(w, http.StatusOK, followsResponse{
+		Users:      users,
+		NextCursor: nextCursor,
+		PrevCursor: prevCursor,
+	})
+}
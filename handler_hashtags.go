@@ -0,0 +1,108 @@
+package main
+
+import (
+	"net/http"
+
+	"github.com/srinivassivaratri/Chirpy/internal/database"
+	"github.com/srinivassivaratri/Chirpy/internal/entities"
+	"github.com/srinivassivaratri/Chirpy/internal/pagination"
+)
+
+func (cfg *apiConfig) handlerHashtagChirps(w http.ResponseWriter, r *http.Request) {
+	type response struct {
+		Chirps     []Chirp `json:"chirps"`
+		NextCursor string  `json:"next_cursor,omitempty"`
+		PrevCursor string  `json:"prev_cursor,omitempty"`
+	}
+
+	tag := entities.NormalizeHashtag(r.PathValue("tag"))
+	if tag == "" {
+		respondWithError(w, http.StatusBadRequest, "Invalid hashtag", nil)
+		return
+	}
+
+	viewerID, err := cfg.viewerID(r)
+	if err != nil {
+		respondWithAuthError(w, err)
+		return
+	}
+
+	limit, cursor, err := parsePage(r)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error(), err)
+		return
+	}
+	cursorCreatedAt, cursorID := cursorPosition(cursor)
+
+	// Newest first
+	var dbChirps []database.Chirp
+	if walkingBack(cursor) {
+		dbChirps, err = cfg.db.ListHashtagChirpsAsc(r.Context(), database.ListHashtagChirpsAscParams{
+			Tag:             tag,
+			CursorCreatedAt: cursorCreatedAt,
+			CursorID:        cursorID,
+			ViewerID:        viewerID,
+			RowLimit:        int32(limit + 1),
+		})
+	} else {
+		dbChirps, err = cfg.db.ListHashtagChirpsDesc(r.Context(), database.ListHashtagChirpsDescParams{
+			Tag:             tag,
+			CursorCreatedAt: cursorCreatedAt,
+			CursorID:        cursorID,
+			ViewerID:        viewerID,
+			RowLimit:        int32(limit + 1),
+		})
+	}
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't retrieve chirps", err)
+		return
+	}
+
+	dbChirps, nextCursor, prevCursor := pagination.Paginate(dbChirps, limit, cursor, func(c database.Chirp) pagination.Cursor {
+		return pagination.Cursor{CreatedAt: c.CreatedAt, ID: c.ID}
+	})
+
+	chirps, err := cfg.chirpsForViewer(r.Context(), viewerID, dbChirps)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't retrieve chirps", err)
+		return
+	}
+
+	setLinkHeader(w, r, nextCursor, prevCursor)
+	respondWithJSON(w, http.StatusOK, response{
+		Chirps:     chirps,
+		NextCursor: nextCursor,
+		PrevCursor: prevCursor,
+	})
+}
+
+func (cfg *apiConfig) handlerTrending(w http.ResponseWriter, r *http.Request) {
+	type trendingHashtag struct {
+		Tag        string  `json:"tag"`
+		Score      float64 `json:"score"`
+		ChirpCount int32   `json:"chirp_count"`
+	}
+
+	limit, err := pagination.ParseLimit(r.URL.Query().Get("limit"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error(), err)
+		return
+	}
+
+	dbTrending, err := cfg.db.ListTrendingHashtags(r.Context(), int32(limit))
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't retrieve trending hashtags", err)
+		return
+	}
+
+	trending := []trendingHashtag{}
+	for _, dbHashtag := range dbTrending {
+		trending = append(trending, trendingHashtag{
+			Tag:        dbHashtag.Tag,
+			Score:      dbHashtag.Score,
+			ChirpCount: dbHashtag.ChirpCount,
+		})
+	}
+
+	respondWithJSON(w, http.StatusOK, trending)
+}
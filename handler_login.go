@@ -1,10 +1,12 @@
 package main
 
 import (
+	"context"
 	"encoding/json"
+	"log"
 	"net/http"
-	"time"
 
+	"github.com/google/uuid"
 	"github.com/srinivassivaratri/Chirpy/internal/auth"
 	"github.com/srinivassivaratri/Chirpy/internal/database"
 )
@@ -34,47 +36,64 @@ func (cfg *apiConfig) handlerLogin(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	err = auth.CheckPasswordHash(params.Password, user.HashedPassword)
+	needsRehash, err := cfg.passwords.Verify(params.Password, user.HashedPassword)
 	if err != nil {
 		respondWithError(w, http.StatusUnauthorized, "Incorrect email or password", err)
 		return
 	}
-
-	accessToken, err := auth.MakeJWT(
-		user.ID,
-		cfg.jwtSecret,
-		time.Hour,
-	)
-	if err != nil {
-		respondWithError(w, http.StatusInternalServerError, "Couldn't create access JWT", err)
-		return
+	// Now's the only time we have the password to upgrade an old hash with
+	if needsRehash {
+		cfg.rehashPassword(r.Context(), user.ID, user.HashedPassword, params.Password)
 	}
 
-	refreshToken, err := auth.MakeRefreshToken()
+	// Each login is a new session with its own family of refresh tokens
+	refreshToken, err := cfg.startSession(r.Context(), user.ID, requestDevice(r))
 	if err != nil {
-		respondWithError(w, http.StatusInternalServerError, "Couldn't create refresh token", err)
+		respondWithError(w, http.StatusInternalServerError, "Couldn't save refresh token", err)
 		return
 	}
 
-	_, err = cfg.db.CreateRefreshToken(r.Context(), database.CreateRefreshTokenParams{
-		UserID:    user.ID,
-		Token:     refreshToken,
-		ExpiresAt: time.Now().UTC().Add(time.Hour * 24 * 60),
-	})
+	accessToken, err := auth.MakeJWT(
+		user.ID,
+		refreshToken.SessionID,
+		cfg.jwtConfig,
+		accessTokenLifetime,
+	)
 	if err != nil {
-		respondWithError(w, http.StatusInternalServerError, "Couldn't save refresh token", err)
+		respondWithError(w, http.StatusInternalServerError, "Couldn't create access JWT", err)
 		return
 	}
 
 	respondWithJSON(w, http.StatusOK, response{
 		User: User{
-			ID:          user.ID,          // Unique identifier for the user, like a social security number
-			CreatedAt:   user.CreatedAt,   // Timestamp of when the user first signed up
-			UpdatedAt:   user.UpdatedAt,   // Timestamp of when the user's info was last changed
-			Email:       user.Email,       // User's email address they use to log in
-			IsChirpyRed: user.IsChirpyRed, // Whether they're a premium member (true) or free user (false)
+			ID:          user.ID,              // Unique identifier for the user, like a social security number
+			CreatedAt:   user.CreatedAt,       // Timestamp of when the user first signed up
+			UpdatedAt:   user.UpdatedAt,       // Timestamp of when the user's info was last changed
+			Email:       user.Email,           // User's email address they use to log in
+			Username:    user.Username.String, // Public handle other users can @mention, if they've picked one
+			IsChirpyRed: user.IsChirpyRed,     // Whether they're a premium member (true) or free user (false)
+			DMPolicy:    user.DmPolicy,        // Who can start a conversation with them
+			Role:        user.Role,            // What they're allowed to do: user, moderator or admin
 		},
 		Token:        accessToken,
-		RefreshToken: refreshToken,
+		RefreshToken: refreshToken.Token,
 	})
 }
+
+// rehashPassword replaces a hash made with bcrypt or old argon2id params.
+// Login has already succeeded, so failing is only logged.
+func (cfg *apiConfig) rehashPassword(ctx context.Context, userID uuid.UUID, oldHash, password string) {
+	newHash, err := cfg.passwords.Hash(password)
+	if err != nil {
+		log.Printf("Error rehashing password: %s", err)
+		return
+	}
+	_, err = cfg.db.UpdateUserPasswordHash(ctx, database.UpdateUserPasswordHashParams{
+		NewHash: newHash,
+		ID:      userID,
+		OldHash: oldHash,
+	})
+	if err != nil {
+		log.Printf("Error saving rehashed password: %s", err)
+	}
+}
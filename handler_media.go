@@ -0,0 +1,213 @@
+package main
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/srinivassivaratri/Chirpy/internal/auth"
+	"github.com/srinivassivaratri/Chirpy/internal/database"
+	"github.com/srinivassivaratri/Chirpy/internal/filter"
+	"github.com/srinivassivaratri/Chirpy/internal/media"
+)
+
+// handlerMediaCreate takes a multipart upload with the image in "file" and
+// optional "alt_text". The image can then be attached to a chirp by its ID.
+func (cfg *apiConfig) handlerMediaCreate(w http.ResponseWriter, r *http.Request) {
+	token, err := auth.GetBearerToken(r.Header)
+	if err != nil {
+		respondWithAuthError(w, err)
+		return
+	}
+	userID, err := cfg.validateJWT(r.Context(), token)
+	if err != nil {
+		respondWithAuthError(w, err)
+		return
+	}
+
+	// Leaves room for the alt text and multipart headers around the image
+	const maxRequestBytes = media.MaxBytes + 64<<10
+	r.Body = http.MaxBytesReader(w, r.Body, maxRequestBytes)
+	err = r.ParseMultipartForm(1 << 20)
+	var maxBytesErr *http.MaxBytesError
+	if errors.As(err, &maxBytesErr) {
+		respondWithError(w, http.StatusRequestEntityTooLarge, media.ErrTooLarge.Error(), err)
+		return
+	}
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Couldn't parse multipart form", err)
+		return
+	}
+	defer r.MultipartForm.RemoveAll()
+
+	file, _, err := r.FormFile("file")
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Couldn't find file", err)
+		return
+	}
+	defer file.Close()
+
+	altText := r.FormValue("alt_text")
+	if filter.Length(altText) > maxAltTextLength {
+		respondWithError(w, http.StatusBadRequest, "Alt text is too long", nil)
+		return
+	}
+
+	original, thumbnail, err := media.Process(file)
+	switch {
+	case errors.Is(err, media.ErrTooLarge):
+		respondWithError(w, http.StatusRequestEntityTooLarge, err.Error(), err)
+		return
+	case errors.Is(err, media.ErrUnsupportedType):
+		respondWithError(w, http.StatusUnsupportedMediaType, err.Error(), err)
+		return
+	case errors.Is(err, media.ErrTooManyPixels), errors.Is(err, media.ErrInvalidImage):
+		respondWithError(w, http.StatusBadRequest, err.Error(), err)
+		return
+	case err != nil:
+		respondWithError(w, http.StatusInternalServerError, "Couldn't process image", err)
+		return
+	}
+
+	id := uuid.New()
+	dbMedia := database.CreateMediaParams{
+		ID:              id,
+		UserID:          userID,
+		ContentType:     original.ContentType,
+		StorageKey:      id.String() + original.Ext(),
+		Width:           int32(original.Width),
+		Height:          int32(original.Height),
+		SizeBytes:       int32(len(original.Data)),
+		ThumbnailKey:    id.String() + "-thumb" + thumbnail.Ext(),
+		ThumbnailWidth:  int32(thumbnail.Width),
+		ThumbnailHeight: int32(thumbnail.Height),
+		AltText:         altText,
+	}
+
+	err = cfg.mediaStorage.Put(r.Context(), dbMedia.StorageKey, original.ContentType, original.Data)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't store image", err)
+		return
+	}
+	err = cfg.mediaStorage.Put(r.Context(), dbMedia.ThumbnailKey, thumbnail.ContentType, thumbnail.Data)
+	if err != nil {
+		cfg.mediaStorage.Delete(r.Context(), dbMedia.StorageKey)
+		respondWithError(w, http.StatusInternalServerError, "Couldn't store thumbnail", err)
+		return
+	}
+
+	created, err := cfg.db.CreateMedia(r.Context(), dbMedia)
+	if err != nil {
+		cfg.mediaStorage.Delete(r.Context(), dbMedia.StorageKey)
+		cfg.mediaStorage.Delete(r.Context(), dbMedia.ThumbnailKey)
+		respondWithError(w, http.StatusInternalServerError, "Couldn't save media", err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusCreated, cfg.databaseMediaToChirpMedia(created))
+}
+
+// handlerMediaUpdate changes an image's alt text, before or after it's
+// attached to a chirp
+func (cfg *apiConfig) handlerMediaUpdate(w http.ResponseWriter, r *http.Request) {
+	type parameters struct {
+		AltText string `json:"alt_text"`
+	}
+
+	mediaIDString := r.PathValue("mediaID")
+	mediaID, err := uuid.Parse(mediaIDString)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid media ID", err)
+		return
+	}
+
+	token, err := auth.GetBearerToken(r.Header)
+	if err != nil {
+		respondWithAuthError(w, err)
+		return
+	}
+	userID, err := cfg.validateJWT(r.Context(), token)
+	if err != nil {
+		respondWithAuthError(w, err)
+		return
+	}
+
+	decoder := json.NewDecoder(r.Body)
+	params := parameters{}
+	err = decoder.Decode(&params)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't decode parameters", err)
+		return
+	}
+	if filter.Length(params.AltText) > maxAltTextLength {
+		respondWithError(w, http.StatusBadRequest, "Alt text is too long", nil)
+		return
+	}
+
+	dbMedia, err := cfg.db.GetMedia(r.Context(), mediaID)
+	if errors.Is(err, sql.ErrNoRows) {
+		respondWithError(w, http.StatusNotFound, "Couldn't find media", err)
+		return
+	}
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't get media", err)
+		return
+	}
+	if dbMedia.UserID != userID {
+		respondWithError(w, http.StatusForbidden, "You can't edit someone else's media", nil)
+		return
+	}
+
+	dbMedia, err = cfg.db.UpdateMediaAltText(r.Context(), database.UpdateMediaAltTextParams{
+		ID:      mediaID,
+		AltText: params.AltText,
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't update media", err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, cfg.databaseMediaToChirpMedia(dbMedia))
+}
+
+// handlerMediaFile serves uploads kept on local disk. Keys are unique, so
+// files can be cached forever.
+func handlerMediaFile(dir string) http.HandlerFunc {
+	files := os.DirFS(dir)
+	return func(w http.ResponseWriter, r *http.Request) {
+		key := r.PathValue("key")
+		// Half written uploads start with a dot
+		if strings.HasPrefix(key, ".") || !fs.ValidPath(key) {
+			respondWithError(w, http.StatusNotFound, "Couldn't find file", nil)
+			return
+		}
+		if _, err := fs.Stat(files, key); err != nil {
+			respondWithError(w, http.StatusNotFound, "Couldn't find file", err)
+			return
+		}
+		w.Header().Set("Cache-Control", "public, max-age=31536000, immutable")
+		w.Header().Set("X-Content-Type-Options", "nosniff")
+		http.ServeFileFS(w, r, files, key)
+	}
+}
+
+// checkChirpMedia checks the media IDs sent with a new chirp
+func checkChirpMedia(mediaIDs []uuid.UUID) error {
+	if len(mediaIDs) > maxChirpMedia {
+		return fmt.Errorf("A chirp can have at most %d images", maxChirpMedia)
+	}
+	seen := map[uuid.UUID]bool{}
+	for _, id := range mediaIDs {
+		if seen[id] {
+			return errors.New("The same image can't be attached twice")
+		}
+		seen[id] = true
+	}
+	return nil
+}
@@ -0,0 +1,266 @@
+package main
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/srinivassivaratri/Chirpy/internal/auth"
+	"github.com/srinivassivaratri/Chirpy/internal/database"
+	"github.com/srinivassivaratri/Chirpy/internal/filter"
+	"github.com/srinivassivaratri/Chirpy/internal/pagination"
+)
+
+func (cfg *apiConfig) handlerMessagesGet(w http.ResponseWriter, r *http.Request) {
+	type response struct {
+		Messages   []Message `json:"messages"`
+		NextCursor string    `json:"next_cursor,omitempty"`
+		PrevCursor string    `json:"prev_cursor,omitempty"`
+	}
+
+	conversationIDString := r.PathValue("conversationID")
+	conversationID, err := uuid.Parse(conversationIDString)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid conversation ID", err)
+		return
+	}
+
+	token, err := auth.GetBearerToken(r.Header)
+	if err != nil {
+		respondWithAuthError(w, err)
+		return
+	}
+	userID, err := cfg.validateJWT(r.Context(), token)
+	if err != nil {
+		respondWithAuthError(w, err)
+		return
+	}
+
+	// Conversations you're not in don't exist as far as you can tell
+	_, err = cfg.db.GetConversationForUser(r.Context(), database.GetConversationForUserParams{
+		ID:     conversationID,
+		UserID: userID,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		respondWithError(w, http.StatusNotFound, "Couldn't find conversation", err)
+		return
+	}
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't get conversation", err)
+		return
+	}
+
+	limit, cursor, err := parsePage(r)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error(), err)
+		return
+	}
+	cursorCreatedAt, cursorID := cursorPosition(cursor)
+
+	// Newest first
+	var dbMessages []database.Message
+	if walkingBack(cursor) {
+		dbMessages, err = cfg.db.ListMessagesAsc(r.Context(), database.ListMessagesAscParams{
+			ConversationID:  conversationID,
+			CursorCreatedAt: cursorCreatedAt,
+			CursorID:        cursorID,
+			RowLimit:        int32(limit + 1),
+		})
+	} else {
+		dbMessages, err = cfg.db.ListMessagesDesc(r.Context(), database.ListMessagesDescParams{
+			ConversationID:  conversationID,
+			CursorCreatedAt: cursorCreatedAt,
+			CursorID:        cursorID,
+			RowLimit:        int32(limit + 1),
+		})
+	}
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't retrieve messages", err)
+		return
+	}
+
+	dbMessages, nextCursor, prevCursor := pagination.Paginate(dbMessages, limit, cursor, func(m database.Message) pagination.Cursor {
+		return pagination.Cursor{CreatedAt: m.CreatedAt, ID: m.ID}
+	})
+
+	messages := []Message{}
+	for _, dbMessage := range dbMessages {
+		messages = append(messages, databaseMessageToMessage(dbMessage))
+	}
+
+	setLinkHeader(w, r, nextCursor, prevCursor)
+	respondWithJSON(w, http.StatusOK, response{
+		Messages:   messages,
+		NextCursor: nextCursor,
+		PrevCursor: prevCursor,
+	})
+}
+
+func (cfg *apiConfig) handlerMessagesCreate(w http.ResponseWriter, r *http.Request) {
+	type parameters struct {
+		Body string `json:"body"`
+	}
+
+	conversationIDString := r.PathValue("conversationID")
+	conversationID, err := uuid.Parse(conversationIDString)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid conversation ID", err)
+		return
+	}
+
+	token, err := auth.GetBearerToken(r.Header)
+	if err != nil {
+		respondWithAuthError(w, err)
+		return
+	}
+	userID, err := cfg.validateJWT(r.Context(), token)
+	if err != nil {
+		respondWithAuthError(w, err)
+		return
+	}
+
+	decoder := json.NewDecoder(r.Body)
+	params := parameters{}
+	err = decoder.Decode(&params)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't decode parameters", err)
+		return
+	}
+
+	filtered, err := cfg.validateMessage(params.Body)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error(), err)
+		return
+	}
+
+	_, err = cfg.db.GetConversationForUser(r.Context(), database.GetConversationForUserParams{
+		ID:     conversationID,
+		UserID: userID,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		respondWithError(w, http.StatusNotFound, "Couldn't find conversation", err)
+		return
+	}
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't get conversation", err)
+		return
+	}
+
+	// Once either of you blocks the other, neither can message the other, even
+	// in a group
+	blocked, err := cfg.db.IsBlockedInConversation(r.Context(), database.IsBlockedInConversationParams{
+		UserID:         userID,
+		ConversationID: conversationID,
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't check blocks", err)
+		return
+	}
+	if blocked {
+		respondWithError(w, http.StatusForbidden, "You can't send messages in this conversation", nil)
+		return
+	}
+
+	message, err := cfg.db.CreateMessage(r.Context(), database.CreateMessageParams{
+		ConversationID: conversationID,
+		SenderID:       userID,
+		Body:           filtered.Text,
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't send message", err)
+		return
+	}
+	cfg.flagForReview(r.Context(), uuid.NullUUID{}, userID, filtered.Flagged)
+
+	respondWithJSON(w, http.StatusCreated, databaseMessageToMessage(message))
+}
+
+// handlerConversationsRead moves the user's read receipt up to a message.
+// Receipts only move forward, so reading an older message changes nothing.
+func (cfg *apiConfig) handlerConversationsRead(w http.ResponseWriter, r *http.Request) {
+	type parameters struct {
+		MessageID uuid.UUID `json:"message_id"`
+	}
+
+	conversationIDString := r.PathValue("conversationID")
+	conversationID, err := uuid.Parse(conversationIDString)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid conversation ID", err)
+		return
+	}
+
+	token, err := auth.GetBearerToken(r.Header)
+	if err != nil {
+		respondWithAuthError(w, err)
+		return
+	}
+	userID, err := cfg.validateJWT(r.Context(), token)
+	if err != nil {
+		respondWithAuthError(w, err)
+		return
+	}
+
+	decoder := json.NewDecoder(r.Body)
+	params := parameters{}
+	err = decoder.Decode(&params)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't decode parameters", err)
+		return
+	}
+
+	dbConversation, err := cfg.db.GetConversationForUser(r.Context(), database.GetConversationForUserParams{
+		ID:     conversationID,
+		UserID: userID,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		respondWithError(w, http.StatusNotFound, "Couldn't find conversation", err)
+		return
+	}
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't get conversation", err)
+		return
+	}
+
+	message, err := cfg.db.GetMessage(r.Context(), params.MessageID)
+	if errors.Is(err, sql.ErrNoRows) || (err == nil && message.ConversationID != conversationID) {
+		respondWithError(w, http.StatusNotFound, "Couldn't find message", err)
+		return
+	}
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't get message", err)
+		return
+	}
+
+	err = cfg.db.MarkConversationRead(r.Context(), database.MarkConversationReadParams{
+		MessageID:      message.ID,
+		ConversationID: conversationID,
+		UserID:         userID,
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't mark conversation read", err)
+		return
+	}
+
+	cfg.respondWithConversation(w, r, http.StatusOK, userID, dbConversation)
+}
+
+// validateMessage checks a message's length and runs it through the content
+// filters
+func (cfg *apiConfig) validateMessage(body string) (filter.Result, error) {
+	const maxMessageLength = 1000
+	if strings.TrimSpace(body) == "" {
+		return filter.Result{}, errors.New("Message is empty")
+	}
+	if filter.Length(body) > maxMessageLength {
+		return filter.Result{}, errors.New("Message is too long")
+	}
+
+	result, err := cfg.contentFilter.Apply(body)
+	if err != nil {
+		return filter.Result{}, errBodyRejected
+	}
+	return result, nil
+}
@@ -0,0 +1,335 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/srinivassivaratri/Chirpy/internal/database"
+	"github.com/srinivassivaratri/Chirpy/internal/moderation"
+	"github.com/srinivassivaratri/Chirpy/internal/pagination"
+)
+
+var errReportDecided = errors.New("Report has already been decided")
+
+// handlerReportsGet is the moderation queue. Open reports come oldest first,
+// resolved ones (status=resolved) newest first.
+func (cfg *apiConfig) handlerReportsGet(w http.ResponseWriter, r *http.Request) {
+	type response struct {
+		Reports    []Report `json:"reports"`
+		NextCursor string   `json:"next_cursor,omitempty"`
+		PrevCursor string   `json:"prev_cursor,omitempty"`
+	}
+
+	moderatorID := requestUserID(r)
+
+	status := r.URL.Query().Get("status")
+	if status != "" && status != "open" && status != "resolved" {
+		respondWithError(w, http.StatusBadRequest, "Status must be open or resolved", nil)
+		return
+	}
+	resolved := status == "resolved"
+
+	limit, cursor, err := parsePage(r)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error(), err)
+		return
+	}
+	cursorCreatedAt, cursorID := cursorPosition(cursor)
+
+	ascending := !resolved
+	if walkingBack(cursor) {
+		ascending = !ascending
+	}
+
+	var dbReports []database.Report
+	if ascending {
+		dbReports, err = cfg.db.ListReportsAsc(r.Context(), database.ListReportsAscParams{
+			Resolved:        resolved,
+			CursorCreatedAt: cursorCreatedAt,
+			CursorID:        cursorID,
+			RowLimit:        int32(limit + 1),
+		})
+	} else {
+		dbReports, err = cfg.db.ListReportsDesc(r.Context(), database.ListReportsDescParams{
+			Resolved:        resolved,
+			CursorCreatedAt: cursorCreatedAt,
+			CursorID:        cursorID,
+			RowLimit:        int32(limit + 1),
+		})
+	}
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't retrieve reports", err)
+		return
+	}
+
+	dbReports, nextCursor, prevCursor := pagination.Paginate(dbReports, limit, cursor, func(report database.Report) pagination.Cursor {
+		return pagination.Cursor{CreatedAt: report.CreatedAt, ID: report.ID}
+	})
+
+	reports, err := cfg.reportsForModerator(r.Context(), moderatorID, dbReports)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't retrieve reports", err)
+		return
+	}
+
+	setLinkHeader(w, r, nextCursor, prevCursor)
+	respondWithJSON(w, http.StatusOK, response{
+		Reports:    reports,
+		NextCursor: nextCursor,
+		PrevCursor: prevCursor,
+	})
+}
+
+func (cfg *apiConfig) handlerReportGet(w http.ResponseWriter, r *http.Request) {
+	reportIDString := r.PathValue("reportID")
+	reportID, err := uuid.Parse(reportIDString)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid report ID", err)
+		return
+	}
+
+	moderatorID := requestUserID(r)
+
+	dbReport, err := cfg.db.GetReport(r.Context(), reportID)
+	if errors.Is(err, sql.ErrNoRows) {
+		respondWithError(w, http.StatusNotFound, "Couldn't find report", err)
+		return
+	}
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't get report", err)
+		return
+	}
+
+	reports, err := cfg.reportsForModerator(r.Context(), moderatorID, []database.Report{dbReport})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't get report", err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, reports[0])
+}
+
+// reportsForModerator converts reports for the response, attaching the
+// chirps they're about
+func (cfg *apiConfig) reportsForModerator(ctx context.Context, moderatorID uuid.UUID, dbReports []database.Report) ([]Report, error) {
+	chirpIDs := []uuid.UUID{}
+	for _, dbReport := range dbReports {
+		if dbReport.ChirpID.Valid {
+			chirpIDs = append(chirpIDs, dbReport.ChirpID.UUID)
+		}
+	}
+
+	chirps := map[uuid.UUID]Chirp{}
+	if len(chirpIDs) > 0 {
+		dbChirps, err := cfg.db.GetChirpsByIDs(ctx, chirpIDs)
+		if err != nil {
+			return nil, err
+		}
+		converted, err := cfg.chirpsForViewer(ctx, uuid.NullUUID{UUID: moderatorID, Valid: true}, dbChirps)
+		if err != nil {
+			return nil, err
+		}
+		for _, chirp := range converted {
+			chirps[chirp.ID] = chirp
+		}
+	}
+
+	reports := []Report{}
+	for _, dbReport := range dbReports {
+		report := databaseReportToReport(dbReport)
+		if dbReport.ChirpID.Valid {
+			if chirp, ok := chirps[dbReport.ChirpID.UUID]; ok {
+				report.Chirp = chirp
+			} else {
+				report.Chirp = ChirpTombstone{ID: dbReport.ChirpID.UUID, Deleted: true}
+			}
+		}
+		reports = append(reports, report)
+	}
+	return reports, nil
+}
+
+// handlerReportDecisionsCreate records a moderator's decision on a report,
+// carries it out, and closes every other open report about the same chirp or
+// user along with it
+func (cfg *apiConfig) handlerReportDecisionsCreate(w http.ResponseWriter, r *http.Request) {
+	type parameters struct {
+		Action string `json:"action"`
+		Note   string `json:"note"`
+	}
+
+	reportIDString := r.PathValue("reportID")
+	reportID, err := uuid.Parse(reportIDString)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid report ID", err)
+		return
+	}
+
+	moderatorID := requestUserID(r)
+
+	decoder := json.NewDecoder(r.Body)
+	params := parameters{}
+	err = decoder.Decode(&params)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't decode parameters", err)
+		return
+	}
+
+	dbReport, err := cfg.db.GetReport(r.Context(), reportID)
+	if errors.Is(err, sql.ErrNoRows) {
+		respondWithError(w, http.StatusNotFound, "Couldn't find report", err)
+		return
+	}
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't get report", err)
+		return
+	}
+	if dbReport.DecisionID.Valid {
+		respondWithError(w, http.StatusConflict, errReportDecided.Error(), nil)
+		return
+	}
+	if !moderation.ValidAction(params.Action, moderation.Target(dbReport.TargetKind)) {
+		respondWithError(w, http.StatusBadRequest, "That action can't be taken on this report", nil)
+		return
+	}
+
+	action := moderation.Action(params.Action)
+	if action == moderation.ActionHide || action == moderation.ActionDelete {
+		_, err := cfg.db.GetChirp(r.Context(), dbReport.ChirpID.UUID)
+		if errors.Is(err, sql.ErrNoRows) {
+			respondWithError(w, http.StatusNotFound, "Couldn't find chirp, it may already be deleted", err)
+			return
+		}
+		if err != nil {
+			respondWithError(w, http.StatusInternalServerError, "Couldn't get chirp", err)
+			return
+		}
+	}
+
+	decision, err := cfg.decideReport(r.Context(), database.CreateModerationDecisionParams{
+		ModeratorID: uuid.NullUUID{UUID: moderatorID, Valid: true},
+		ReportID:    dbReport.ID,
+		TargetKind:  dbReport.TargetKind,
+		ChirpID:     dbReport.ChirpID,
+		UserID:      dbReport.UserID,
+		Action:      params.Action,
+		Note:        params.Note,
+	})
+	if errors.Is(err, errReportDecided) {
+		respondWithError(w, http.StatusConflict, err.Error(), err)
+		return
+	}
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't record decision", err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusCreated, databaseDecisionToDecision(decision))
+}
+
+// decideReport records the decision, closes the open reports it covers and
+// hides or deletes the chirp, all or nothing
+func (cfg *apiConfig) decideReport(ctx context.Context, params database.CreateModerationDecisionParams) (database.ModerationDecision, error) {
+	tx, err := cfg.dbConn.BeginTx(ctx, nil)
+	if err != nil {
+		return database.ModerationDecision{}, err
+	}
+	defer tx.Rollback()
+	qtx := cfg.db.WithTx(tx)
+
+	decision, err := qtx.CreateModerationDecision(ctx, params)
+	if err != nil {
+		return database.ModerationDecision{}, err
+	}
+	// Another moderator deciding at the same time closes the reports first
+	resolved, err := qtx.ResolveReports(ctx, database.ResolveReportsParams{
+		DecisionID: uuid.NullUUID{UUID: decision.ID, Valid: true},
+		TargetKind: decision.TargetKind,
+		ChirpID:    decision.ChirpID,
+		UserID:     decision.UserID,
+	})
+	if err != nil {
+		return database.ModerationDecision{}, err
+	}
+	if resolved == 0 {
+		return database.ModerationDecision{}, errReportDecided
+	}
+
+	eventKind := ""
+	switch moderation.Action(decision.Action) {
+	case moderation.ActionHide:
+		err = hideChirp(ctx, qtx, decision.ChirpID.UUID)
+		eventKind = chirpEventHidden
+	case moderation.ActionDelete:
+		err = removeChirp(ctx, qtx, decision.ChirpID.UUID)
+		eventKind = chirpEventDeleted
+	}
+	if err != nil {
+		return database.ModerationDecision{}, err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return database.ModerationDecision{}, err
+	}
+	if eventKind != "" {
+		cfg.publishChirpEvent(ctx, eventKind, decision.ChirpID.UUID)
+	}
+	return decision, nil
+}
+
+// handlerModerationDecisionsGet lists every decision moderators have made,
+// newest first
+func (cfg *apiConfig) handlerModerationDecisionsGet(w http.ResponseWriter, r *http.Request) {
+	type response struct {
+		Decisions  []ModerationDecision `json:"decisions"`
+		NextCursor string               `json:"next_cursor,omitempty"`
+		PrevCursor string               `json:"prev_cursor,omitempty"`
+	}
+
+	limit, cursor, err := parsePage(r)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error(), err)
+		return
+	}
+	cursorCreatedAt, cursorID := cursorPosition(cursor)
+
+	var dbDecisions []database.ModerationDecision
+	if walkingBack(cursor) {
+		dbDecisions, err = cfg.db.ListModerationDecisionsAsc(r.Context(), database.ListModerationDecisionsAscParams{
+			CursorCreatedAt: cursorCreatedAt,
+			CursorID:        cursorID,
+			RowLimit:        int32(limit + 1),
+		})
+	} else {
+		dbDecisions, err = cfg.db.ListModerationDecisionsDesc(r.Context(), database.ListModerationDecisionsDescParams{
+			CursorCreatedAt: cursorCreatedAt,
+			CursorID:        cursorID,
+			RowLimit:        int32(limit + 1),
+		})
+	}
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't retrieve decisions", err)
+		return
+	}
+
+	dbDecisions, nextCursor, prevCursor := pagination.Paginate(dbDecisions, limit, cursor, func(d database.ModerationDecision) pagination.Cursor {
+		return pagination.Cursor{CreatedAt: d.CreatedAt, ID: d.ID}
+	})
+
+	decisions := []ModerationDecision{}
+	for _, dbDecision := range dbDecisions {
+		decisions = append(decisions, databaseDecisionToDecision(dbDecision))
+	}
+
+	setLinkHeader(w, r, nextCursor, prevCursor)
+	respondWithJSON(w, http.StatusOK, response{
+		Decisions:  decisions,
+		NextCursor: nextCursor,
+		PrevCursor: prevCursor,
+	})
+}
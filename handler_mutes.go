@@ -0,0 +1,167 @@
+package main
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/srinivassivaratri/Chirpy/internal/auth"
+	"github.com/srinivassivaratri/Chirpy/internal/database"
+	"github.com/srinivassivaratri/Chirpy/internal/pagination"
+)
+
+// Mute is one entry in the list of users someone has muted
+type Mute struct {
+	UserID  uuid.UUID `json:"user_id"`
+	MutedAt time.Time `json:"muted_at"`
+}
+
+// handlerMutesCreate mutes a user. Their chirps and notifications are hidden
+// from the caller, but unlike a block nothing changes for them.
+func (cfg *apiConfig) handlerMutesCreate(w http.ResponseWriter, r *http.Request) {
+	mutedIDString := r.PathValue("userID")
+	mutedID, err := uuid.Parse(mutedIDString)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid user ID", err)
+		return
+	}
+
+	token, err := auth.GetBearerToken(r.Header)
+	if err != nil {
+		respondWithAuthError(w, err)
+		return
+	}
+	userID, err := cfg.validateJWT(r.Context(), token)
+	if err != nil {
+		respondWithAuthError(w, err)
+		return
+	}
+
+	if mutedID == userID {
+		respondWithError(w, http.StatusBadRequest, "You can't mute yourself", nil)
+		return
+	}
+
+	_, err = cfg.db.GetUser(r.Context(), mutedID)
+	if errors.Is(err, sql.ErrNoRows) {
+		respondWithError(w, http.StatusNotFound, "Couldn't find user", err)
+		return
+	}
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't get user", err)
+		return
+	}
+
+	err = cfg.db.CreateMute(r.Context(), database.CreateMuteParams{
+		MuterID: userID,
+		MutedID: mutedID,
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't mute user", err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (cfg *apiConfig) handlerMutesDelete(w http.ResponseWriter, r *http.Request) {
+	mutedIDString := r.PathValue("userID")
+	mutedID, err := uuid.Parse(mutedIDString)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid user ID", err)
+		return
+	}
+
+	token, err := auth.GetBearerToken(r.Header)
+	if err != nil {
+		respondWithAuthError(w, err)
+		return
+	}
+	userID, err := cfg.validateJWT(r.Context(), token)
+	if err != nil {
+		respondWithAuthError(w, err)
+		return
+	}
+
+	err = cfg.db.DeleteMute(r.Context(), database.DeleteMuteParams{
+		MuterID: userID,
+		MutedID: mutedID,
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't unmute user", err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handlerMutesGet lists the users the caller has muted. Nobody else can see
+// who someone has muted.
+func (cfg *apiConfig) handlerMutesGet(w http.ResponseWriter, r *http.Request) {
+	type response struct {
+		Users      []Mute `json:"users"`
+		NextCursor string `json:"next_cursor,omitempty"`
+		PrevCursor string `json:"prev_cursor,omitempty"`
+	}
+
+	token, err := auth.GetBearerToken(r.Header)
+	if err != nil {
+		respondWithAuthError(w, err)
+		return
+	}
+	userID, err := cfg.validateJWT(r.Context(), token)
+	if err != nil {
+		respondWithAuthError(w, err)
+		return
+	}
+
+	limit, cursor, err := parsePage(r)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error(), err)
+		return
+	}
+	cursorCreatedAt, cursorID := cursorPosition(cursor)
+
+	// Newest mutes first
+	var dbMutes []database.Mute
+	if walkingBack(cursor) {
+		dbMutes, err = cfg.db.ListMutesAsc(r.Context(), database.ListMutesAscParams{
+			UserID:          userID,
+			CursorCreatedAt: cursorCreatedAt,
+			CursorID:        cursorID,
+			RowLimit:        int32(limit + 1),
+		})
+	} else {
+		dbMutes, err = cfg.db.ListMutesDesc(r.Context(), database.ListMutesDescParams{
+			UserID:          userID,
+			CursorCreatedAt: cursorCreatedAt,
+			CursorID:        cursorID,
+			RowLimit:        int32(limit + 1),
+		})
+	}
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't get muted users", err)
+		return
+	}
+
+	dbMutes, nextCursor, prevCursor := pagination.Paginate(dbMutes, limit, cursor, func(m database.Mute) pagination.Cursor {
+		return pagination.Cursor{CreatedAt: m.CreatedAt, ID: m.MutedID}
+	})
+
+	users := []Mute{}
+	for _, dbMute := range dbMutes {
+		users = append(users, Mute{
+			UserID:  dbMute.MutedID,
+			MutedAt: dbMute.CreatedAt,
+		})
+	}
+
+	setLinkHeader(w, r, nextCursor, prevCursor)
+	respondWithJSON(w, http.StatusOK, response{
+		Users:      users,
+		NextCursor: nextCursor,
+		PrevCursor: prevCursor,
+	})
+}
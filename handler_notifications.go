@@ -0,0 +1,215 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/srinivassivaratri/Chirpy/internal/auth"
+	"github.com/srinivassivaratri/Chirpy/internal/database"
+	"github.com/srinivassivaratri/Chirpy/internal/pagination"
+)
+
+type Notification struct {
+	ID        uuid.UUID  `json:"id"`
+	CreatedAt time.Time  `json:"created_at"`
+	Kind      string     `json:"kind"`
+	ActorID   *uuid.UUID `json:"actor_id"`
+	ChirpID   *uuid.UUID `json:"chirp_id"`
+	Read      bool       `json:"read"`
+}
+
+func databaseNotificationToNotification(dbNotification database.Notification) Notification {
+	notification := Notification{
+		ID:        dbNotification.ID,
+		CreatedAt: dbNotification.CreatedAt,
+		Kind:      dbNotification.Kind,
+		Read:      dbNotification.ReadAt.Valid,
+	}
+	if dbNotification.ActorID.Valid {
+		actorID := dbNotification.ActorID.UUID
+		notification.ActorID = &actorID
+	}
+	if dbNotification.ChirpID.Valid {
+		chirpID := dbNotification.ChirpID.UUID
+		notification.ChirpID = &chirpID
+	}
+	return notification
+}
+
+func (cfg *apiConfig) handlerNotificationsGet(w http.ResponseWriter, r *http.Request) {
+	type response struct {
+		Notifications []Notification `json:"notifications"`
+		UnreadCount   int64          `json:"unread_count"`
+		NextCursor    string         `json:"next_cursor,omitempty"`
+		PrevCursor    string         `json:"prev_cursor,omitempty"`
+		// ReadCursor marks everything up to the newest notification on this page
+		ReadCursor string `json:"read_cursor,omitempty"`
+	}
+
+	token, err := auth.GetBearerToken(r.Header)
+	if err != nil {
+		respondWithAuthError(w, err)
+		return
+	}
+	userID, err := cfg.validateJWT(r.Context(), token)
+	if err != nil {
+		respondWithAuthError(w, err)
+		return
+	}
+
+	limit, cursor, err := parsePage(r)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error(), err)
+		return
+	}
+	cursorCreatedAt, cursorID := cursorPosition(cursor)
+	unreadOnly := r.URL.Query().Get("unread") == "true"
+
+	// Newest first
+	var dbNotifications []database.Notification
+	if walkingBack(cursor) {
+		dbNotifications, err = cfg.db.ListNotificationsAsc(r.Context(), database.ListNotificationsAscParams{
+			UserID:          userID,
+			UnreadOnly:      unreadOnly,
+			CursorCreatedAt: cursorCreatedAt,
+			CursorID:        cursorID,
+			RowLimit:        int32(limit + 1),
+		})
+	} else {
+		dbNotifications, err = cfg.db.ListNotificationsDesc(r.Context(), database.ListNotificationsDescParams{
+			UserID:          userID,
+			UnreadOnly:      unreadOnly,
+			CursorCreatedAt: cursorCreatedAt,
+			CursorID:        cursorID,
+			RowLimit:        int32(limit + 1),
+		})
+	}
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't retrieve notifications", err)
+		return
+	}
+
+	dbNotifications, nextCursor, prevCursor := pagination.Paginate(dbNotifications, limit, cursor, func(n database.Notification) pagination.Cursor {
+		return pagination.Cursor{CreatedAt: n.CreatedAt, ID: n.ID}
+	})
+
+	unreadCount, err := cfg.db.CountUnreadNotifications(r.Context(), userID)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't count unread notifications", err)
+		return
+	}
+
+	notifications := []Notification{}
+	for _, dbNotification := range dbNotifications {
+		notifications = append(notifications, databaseNotificationToNotification(dbNotification))
+	}
+
+	readCursor := ""
+	if len(dbNotifications) > 0 {
+		readCursor = pagination.Cursor{
+			CreatedAt: dbNotifications[0].CreatedAt,
+			ID:        dbNotifications[0].ID,
+			Direction: pagination.DirectionNext,
+		}.Encode()
+	}
+
+	setLinkHeader(w, r, nextCursor, prevCursor)
+	respondWithJSON(w, http.StatusOK, response{
+		Notifications: notifications,
+		UnreadCount:   unreadCount,
+		NextCursor:    nextCursor,
+		PrevCursor:    prevCursor,
+		ReadCursor:    readCursor,
+	})
+}
+
+func (cfg *apiConfig) handlerNotificationsRead(w http.ResponseWriter, r *http.Request) {
+	type parameters struct {
+		Cursor string `json:"cursor"`
+	}
+	type response struct {
+		UnreadCount int64 `json:"unread_count"`
+	}
+
+	token, err := auth.GetBearerToken(r.Header)
+	if err != nil {
+		respondWithAuthError(w, err)
+		return
+	}
+	userID, err := cfg.validateJWT(r.Context(), token)
+	if err != nil {
+		respondWithAuthError(w, err)
+		return
+	}
+
+	// No body, or no cursor, marks everything read
+	decoder := json.NewDecoder(r.Body)
+	params := parameters{}
+	err = decoder.Decode(&params)
+	if err != nil && !errors.Is(err, io.EOF) {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't decode parameters", err)
+		return
+	}
+
+	var cursor *pagination.Cursor
+	if params.Cursor != "" {
+		c, err := pagination.Decode(params.Cursor)
+		if err != nil {
+			respondWithError(w, http.StatusBadRequest, err.Error(), err)
+			return
+		}
+		cursor = &c
+	}
+	throughCreatedAt, throughID := cursorPosition(cursor)
+
+	err = cfg.db.MarkNotificationsRead(r.Context(), database.MarkNotificationsReadParams{
+		UserID:           userID,
+		ThroughCreatedAt: throughCreatedAt,
+		ThroughID:        throughID,
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't mark notifications read", err)
+		return
+	}
+
+	unreadCount, err := cfg.db.CountUnreadNotifications(r.Context(), userID)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't count unread notifications", err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, response{
+		UnreadCount: unreadCount,
+	})
+}
+
+func (cfg *apiConfig) handlerNotificationsUnreadCount(w http.ResponseWriter, r *http.Request) {
+	type response struct {
+		UnreadCount int64 `json:"unread_count"`
+	}
+
+	token, err := auth.GetBearerToken(r.Header)
+	if err != nil {
+		respondWithAuthError(w, err)
+		return
+	}
+	userID, err := cfg.validateJWT(r.Context(), token)
+	if err != nil {
+		respondWithAuthError(w, err)
+		return
+	}
+
+	unreadCount, err := cfg.db.CountUnreadNotifications(r.Context(), userID)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't count unread notifications", err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, response{
+		UnreadCount: unreadCount,
+	})
+}
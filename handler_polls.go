@@ -0,0 +1,112 @@
+package main
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/srinivassivaratri/Chirpy/internal/auth"
+	"github.com/srinivassivaratri/Chirpy/internal/database"
+	"github.com/srinivassivaratri/Chirpy/internal/polls"
+)
+
+// handlerPollVotesCreate votes in the poll on a chirp. Voting again changes
+// the vote, until the poll closes.
+func (cfg *apiConfig) handlerPollVotesCreate(w http.ResponseWriter, r *http.Request) {
+	type parameters struct {
+		Option *int32 `json:"option"`
+	}
+
+	chirpIDString := r.PathValue("chirpID")
+	chirpID, err := uuid.Parse(chirpIDString)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid chirp ID", err)
+		return
+	}
+
+	token, err := auth.GetBearerToken(r.Header)
+	if err != nil {
+		respondWithAuthError(w, err)
+		return
+	}
+	userID, err := cfg.validateJWT(r.Context(), token)
+	if err != nil {
+		respondWithAuthError(w, err)
+		return
+	}
+	viewerID := uuid.NullUUID{UUID: userID, Valid: true}
+
+	decoder := json.NewDecoder(r.Body)
+	params := parameters{}
+	err = decoder.Decode(&params)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't decode parameters", err)
+		return
+	}
+	if params.Option == nil {
+		respondWithError(w, http.StatusBadRequest, "Pick an option to vote for", nil)
+		return
+	}
+
+	dbChirp, err := cfg.db.GetChirp(r.Context(), chirpID)
+	if errors.Is(err, sql.ErrNoRows) {
+		respondWithError(w, http.StatusNotFound, "Couldn't get chirp", err)
+		return
+	}
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't get chirp", err)
+		return
+	}
+	if cfg.respondIfHidden(w, r, viewerID, dbChirp) {
+		return
+	}
+
+	poll, err := cfg.db.GetPoll(r.Context(), chirpID)
+	if errors.Is(err, sql.ErrNoRows) {
+		respondWithError(w, http.StatusNotFound, "Chirp doesn't have a poll", err)
+		return
+	}
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't get poll", err)
+		return
+	}
+	if polls.Closed(poll.ClosesAt, time.Now().UTC()) {
+		respondWithError(w, http.StatusConflict, "Poll has closed", nil)
+		return
+	}
+	options, err := cfg.db.ListPollOptions(r.Context(), []uuid.UUID{chirpID})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't get poll", err)
+		return
+	}
+	if *params.Option < 0 || int(*params.Option) >= len(options) {
+		respondWithError(w, http.StatusBadRequest, "Poll doesn't have that option", nil)
+		return
+	}
+
+	// The database has the last word on whether the poll is still open
+	cast, err := cfg.db.CastPollVote(r.Context(), database.CastPollVoteParams{
+		UserID:   userID,
+		Position: *params.Option,
+		ChirpID:  chirpID,
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't save vote", err)
+		return
+	}
+	if cast == 0 {
+		respondWithError(w, http.StatusConflict, "Poll has closed", nil)
+		return
+	}
+
+	chirps, err := cfg.chirpsForViewer(r.Context(), viewerID, []database.Chirp{dbChirp})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't get chirp", err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, chirps[0])
+}
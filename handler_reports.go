@@ -0,0 +1,157 @@
+package main
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/srinivassivaratri/Chirpy/internal/auth"
+	"github.com/srinivassivaratri/Chirpy/internal/database"
+	"github.com/srinivassivaratri/Chirpy/internal/moderation"
+)
+
+// Longest explanation a reporter can add
+const maxReportDetailsLength = 1000
+
+type reportParameters struct {
+	Reason  string `json:"reason"`
+	Details string `json:"details"`
+}
+
+func (cfg *apiConfig) handlerChirpReportsCreate(w http.ResponseWriter, r *http.Request) {
+	chirpIDString := r.PathValue("chirpID")
+	chirpID, err := uuid.Parse(chirpIDString)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid chirp ID", err)
+		return
+	}
+
+	token, err := auth.GetBearerToken(r.Header)
+	if err != nil {
+		respondWithAuthError(w, err)
+		return
+	}
+	userID, err := cfg.validateJWT(r.Context(), token)
+	if err != nil {
+		respondWithAuthError(w, err)
+		return
+	}
+
+	params, ok := decodeReportParameters(w, r)
+	if !ok {
+		return
+	}
+
+	dbChirp, err := cfg.db.GetChirp(r.Context(), chirpID)
+	if errors.Is(err, sql.ErrNoRows) {
+		respondWithError(w, http.StatusNotFound, "Couldn't get chirp", err)
+		return
+	}
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't get chirp", err)
+		return
+	}
+	if dbChirp.UserID == userID {
+		respondWithError(w, http.StatusBadRequest, "You can't report your own chirp", nil)
+		return
+	}
+
+	report, err := cfg.db.CreateReport(r.Context(), database.CreateReportParams{
+		ReporterID: uuid.NullUUID{UUID: userID, Valid: true},
+		TargetKind: string(moderation.TargetChirp),
+		ChirpID:    uuid.NullUUID{UUID: dbChirp.ID, Valid: true},
+		UserID:     dbChirp.UserID,
+		Reason:     params.Reason,
+		Details:    params.Details,
+	})
+	if isUniqueViolation(err) {
+		respondWithError(w, http.StatusConflict, "You've already reported this chirp", err)
+		return
+	}
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't create report", err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusCreated, databaseReportToReport(report))
+}
+
+func (cfg *apiConfig) handlerUserReportsCreate(w http.ResponseWriter, r *http.Request) {
+	reportedIDString := r.PathValue("userID")
+	reportedID, err := uuid.Parse(reportedIDString)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid user ID", err)
+		return
+	}
+
+	token, err := auth.GetBearerToken(r.Header)
+	if err != nil {
+		respondWithAuthError(w, err)
+		return
+	}
+	userID, err := cfg.validateJWT(r.Context(), token)
+	if err != nil {
+		respondWithAuthError(w, err)
+		return
+	}
+
+	params, ok := decodeReportParameters(w, r)
+	if !ok {
+		return
+	}
+
+	if reportedID == userID {
+		respondWithError(w, http.StatusBadRequest, "You can't report yourself", nil)
+		return
+	}
+	_, err = cfg.db.GetUser(r.Context(), reportedID)
+	if errors.Is(err, sql.ErrNoRows) {
+		respondWithError(w, http.StatusNotFound, "Couldn't find user", err)
+		return
+	}
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't get user", err)
+		return
+	}
+
+	report, err := cfg.db.CreateReport(r.Context(), database.CreateReportParams{
+		ReporterID: uuid.NullUUID{UUID: userID, Valid: true},
+		TargetKind: string(moderation.TargetUser),
+		UserID:     reportedID,
+		Reason:     params.Reason,
+		Details:    params.Details,
+	})
+	if isUniqueViolation(err) {
+		respondWithError(w, http.StatusConflict, "You've already reported this user", err)
+		return
+	}
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't create report", err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusCreated, databaseReportToReport(report))
+}
+
+// decodeReportParameters reads and checks a report's reason and details,
+// responding with an error and returning false if they're no good
+func decodeReportParameters(w http.ResponseWriter, r *http.Request) (reportParameters, bool) {
+	decoder := json.NewDecoder(r.Body)
+	params := reportParameters{}
+	err := decoder.Decode(&params)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't decode parameters", err)
+		return reportParameters{}, false
+	}
+	if !moderation.ValidReason(params.Reason) {
+		respondWithError(w, http.StatusBadRequest, "Unknown report reason", nil)
+		return reportParameters{}, false
+	}
+	if len(params.Details) > maxReportDetailsLength {
+		respondWithError(w, http.StatusBadRequest, "Report details are too long", nil)
+		return reportParameters{}, false
+	}
+	return params, true
+}
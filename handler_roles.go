@@ -0,0 +1,168 @@
+package main
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/srinivassivaratri/Chirpy/internal/database"
+	"github.com/srinivassivaratri/Chirpy/internal/pagination"
+	"github.com/srinivassivaratri/Chirpy/internal/rbac"
+)
+
+// UserRole is a user and the role they have
+type UserRole struct {
+	UserID   uuid.UUID `json:"user_id"`
+	Username string    `json:"username,omitempty"`
+	Role     string    `json:"role"`
+}
+
+func databaseUserToUserRole(user database.User) UserRole {
+	return UserRole{
+		UserID:   user.ID,
+		Username: user.Username.String,
+		Role:     user.Role,
+	}
+}
+
+// handlerUserRolesCreate gives a user a role, replacing the one they had
+func (cfg *apiConfig) handlerUserRolesCreate(w http.ResponseWriter, r *http.Request) {
+	type parameters struct {
+		Role string `json:"role"`
+	}
+
+	userIDString := r.PathValue("userID")
+	userID, err := uuid.Parse(userIDString)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid user ID", err)
+		return
+	}
+
+	decoder := json.NewDecoder(r.Body)
+	params := parameters{}
+	err = decoder.Decode(&params)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't decode parameters", err)
+		return
+	}
+	if !rbac.ValidRole(params.Role) || rbac.Role(params.Role) == rbac.RoleUser {
+		respondWithError(w, http.StatusBadRequest, "Role must be moderator or admin", nil)
+		return
+	}
+
+	// Admins stepping down would risk leaving nobody able to manage roles
+	if userID == requestUserID(r) {
+		respondWithError(w, http.StatusBadRequest, "You can't change your own role", nil)
+		return
+	}
+
+	user, err := cfg.db.SetUserRole(r.Context(), database.SetUserRoleParams{
+		ID:   userID,
+		Role: params.Role,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		respondWithError(w, http.StatusNotFound, "Couldn't find user", err)
+		return
+	}
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't set role", err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, databaseUserToUserRole(user))
+}
+
+// handlerUserRolesDelete takes a role away, leaving the user an ordinary user
+func (cfg *apiConfig) handlerUserRolesDelete(w http.ResponseWriter, r *http.Request) {
+	userIDString := r.PathValue("userID")
+	userID, err := uuid.Parse(userIDString)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid user ID", err)
+		return
+	}
+	role := r.PathValue("role")
+
+	if userID == requestUserID(r) {
+		respondWithError(w, http.StatusBadRequest, "You can't change your own role", nil)
+		return
+	}
+
+	user, err := cfg.db.GetUser(r.Context(), userID)
+	if errors.Is(err, sql.ErrNoRows) {
+		respondWithError(w, http.StatusNotFound, "Couldn't find user", err)
+		return
+	}
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't get user", err)
+		return
+	}
+	if user.Role != role || rbac.Role(role) == rbac.RoleUser {
+		respondWithError(w, http.StatusNotFound, "User doesn't have that role", nil)
+		return
+	}
+
+	user, err = cfg.db.SetUserRole(r.Context(), database.SetUserRoleParams{
+		ID:   userID,
+		Role: string(rbac.RoleUser),
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't set role", err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, databaseUserToUserRole(user))
+}
+
+// handlerRolesGet lists everyone who is a moderator or admin, newest
+// accounts first
+func (cfg *apiConfig) handlerRolesGet(w http.ResponseWriter, r *http.Request) {
+	type response struct {
+		Users      []UserRole `json:"users"`
+		NextCursor string     `json:"next_cursor,omitempty"`
+		PrevCursor string     `json:"prev_cursor,omitempty"`
+	}
+
+	limit, cursor, err := parsePage(r)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error(), err)
+		return
+	}
+	cursorCreatedAt, cursorID := cursorPosition(cursor)
+
+	var dbUsers []database.User
+	if walkingBack(cursor) {
+		dbUsers, err = cfg.db.ListUsersWithRolesAsc(r.Context(), database.ListUsersWithRolesAscParams{
+			CursorCreatedAt: cursorCreatedAt,
+			CursorID:        cursorID,
+			RowLimit:        int32(limit + 1),
+		})
+	} else {
+		dbUsers, err = cfg.db.ListUsersWithRolesDesc(r.Context(), database.ListUsersWithRolesDescParams{
+			CursorCreatedAt: cursorCreatedAt,
+			CursorID:        cursorID,
+			RowLimit:        int32(limit + 1),
+		})
+	}
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't get users", err)
+		return
+	}
+
+	dbUsers, nextCursor, prevCursor := pagination.Paginate(dbUsers, limit, cursor, func(u database.User) pagination.Cursor {
+		return pagination.Cursor{CreatedAt: u.CreatedAt, ID: u.ID}
+	})
+
+	users := []UserRole{}
+	for _, dbUser := range dbUsers {
+		users = append(users, databaseUserToUserRole(dbUser))
+	}
+
+	setLinkHeader(w, r, nextCursor, prevCursor)
+	respondWithJSON(w, http.StatusOK, response{
+		Users:      users,
+		NextCursor: nextCursor,
+		PrevCursor: prevCursor,
+	})
+}
@@ -0,0 +1,171 @@
+package main
+
+import (
+	"database/sql"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/srinivassivaratri/Chirpy/internal/database"
+	"github.com/srinivassivaratri/Chirpy/internal/pagination"
+)
+
+// SearchResult is a chirp matching a search, with how well it matched and
+// an HTML snippet that wraps the matching words in <mark> tags
+type SearchResult struct {
+	Chirp
+	Rank    float32 `json:"rank"`
+	Snippet string  `json:"snippet"`
+}
+
+func (cfg *apiConfig) handlerSearchChirps(w http.ResponseWriter, r *http.Request) {
+	type response struct {
+		Results    []SearchResult `json:"results"`
+		NextCursor string         `json:"next_cursor,omitempty"`
+		PrevCursor string         `json:"prev_cursor,omitempty"`
+	}
+
+	query := r.URL.Query().Get("q")
+	if query == "" {
+		respondWithError(w, http.StatusBadRequest, "Missing search query", nil)
+		return
+	}
+
+	viewerID, err := cfg.viewerID(r)
+	if err != nil {
+		respondWithAuthError(w, err)
+		return
+	}
+
+	authorID := uuid.NullUUID{}
+	if authorIDStr := r.URL.Query().Get("author_id"); authorIDStr != "" {
+		id, err := uuid.Parse(authorIDStr)
+		if err != nil {
+			respondWithError(w, http.StatusBadRequest, "Invalid author ID format", err)
+			return
+		}
+		authorID = uuid.NullUUID{UUID: id, Valid: true}
+	}
+
+	since, err := parseTimeParam(r, "since")
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid since time, use RFC 3339", err)
+		return
+	}
+	until, err := parseTimeParam(r, "until")
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid until time, use RFC 3339", err)
+		return
+	}
+
+	limit, cursor, err := parsePage(r)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error(), err)
+		return
+	}
+	cursorCreatedAt, cursorID := cursorPosition(cursor)
+	cursorRank := sql.NullFloat64{}
+	if cursor != nil {
+		if cursor.Rank == nil {
+			respondWithError(w, http.StatusBadRequest, pagination.ErrInvalidCursor.Error(), nil)
+			return
+		}
+		cursorRank = sql.NullFloat64{Float64: *cursor.Rank, Valid: true}
+	}
+
+	// Best matches first, newest first between equally good matches
+	var rows []database.SearchChirpsDescRow
+	if walkingBack(cursor) {
+		ascRows, err := cfg.db.SearchChirpsAsc(r.Context(), database.SearchChirpsAscParams{
+			Query:           query,
+			AuthorID:        authorID,
+			Since:           since,
+			Until:           until,
+			CursorRank:      cursorRank,
+			CursorCreatedAt: cursorCreatedAt,
+			CursorID:        cursorID,
+			ViewerID:        viewerID,
+			RowLimit:        int32(limit + 1),
+		})
+		if err != nil {
+			respondWithError(w, http.StatusInternalServerError, "Couldn't search chirps", err)
+			return
+		}
+		for _, row := range ascRows {
+			rows = append(rows, database.SearchChirpsDescRow(row))
+		}
+	} else {
+		rows, err = cfg.db.SearchChirpsDesc(r.Context(), database.SearchChirpsDescParams{
+			Query:           query,
+			AuthorID:        authorID,
+			Since:           since,
+			Until:           until,
+			CursorRank:      cursorRank,
+			CursorCreatedAt: cursorCreatedAt,
+			CursorID:        cursorID,
+			ViewerID:        viewerID,
+			RowLimit:        int32(limit + 1),
+		})
+		if err != nil {
+			respondWithError(w, http.StatusInternalServerError, "Couldn't search chirps", err)
+			return
+		}
+	}
+
+	rows, nextCursor, prevCursor := pagination.Paginate(rows, limit, cursor, func(row database.SearchChirpsDescRow) pagination.Cursor {
+		rank := float64(row.Rank)
+		return pagination.Cursor{Rank: &rank, CreatedAt: row.CreatedAt, ID: row.ID}
+	})
+
+	dbChirps := []database.Chirp{}
+	for _, row := range rows {
+		dbChirps = append(dbChirps, database.Chirp{
+			ID:           row.ID,
+			CreatedAt:    row.CreatedAt,
+			UpdatedAt:    row.UpdatedAt,
+			Body:         row.Body,
+			UserID:       row.UserID,
+			InReplyTo:    row.InReplyTo,
+			ThreadID:     row.ThreadID,
+			AncestorIds:  row.AncestorIds,
+			LikeCount:    row.LikeCount,
+			RechirpOf:    row.RechirpOf,
+			Version:      row.Version,
+			SearchVector: row.SearchVector,
+		})
+	}
+	chirps, err := cfg.chirpsForViewer(r.Context(), viewerID, dbChirps)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't search chirps", err)
+		return
+	}
+
+	results := []SearchResult{}
+	for i, chirp := range chirps {
+		results = append(results, SearchResult{
+			Chirp:   chirp,
+			Rank:    rows[i].Rank,
+			Snippet: rows[i].Snippet,
+		})
+	}
+
+	setLinkHeader(w, r, nextCursor, prevCursor)
+	respondWithJSON(w, http.StatusOK, response{
+		Results:    results,
+		NextCursor: nextCursor,
+		PrevCursor: prevCursor,
+	})
+}
+
+// parseTimeParam reads an optional RFC 3339 time from the query string
+func parseTimeParam(r *http.Request, name string) (sql.NullTime, error) {
+	value := r.URL.Query().Get(name)
+	if value == "" {
+		return sql.NullTime{}, nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return sql.NullTime{}, err
+	}
+	return sql.NullTime{Time: t.UTC(), Valid: true}, nil
+}
@@ -0,0 +1,138 @@
+package main
+
+import (
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/srinivassivaratri/Chirpy/internal/auth"
+	"github.com/srinivassivaratri/Chirpy/internal/database"
+	"github.com/srinivassivaratri/Chirpy/internal/pagination"
+)
+
+// handlerSessionsGet lists the devices the caller is logged in on, newest
+// login first
+func (cfg *apiConfig) handlerSessionsGet(w http.ResponseWriter, r *http.Request) {
+	type response struct {
+		Sessions   []Session `json:"sessions"`
+		NextCursor string    `json:"next_cursor,omitempty"`
+		PrevCursor string    `json:"prev_cursor,omitempty"`
+	}
+
+	token, err := auth.GetBearerToken(r.Header)
+	if err != nil {
+		respondWithAuthError(w, err)
+		return
+	}
+	claims, err := cfg.authenticate(r.Context(), token)
+	if err != nil {
+		respondWithAuthError(w, err)
+		return
+	}
+
+	limit, cursor, err := parsePage(r)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error(), err)
+		return
+	}
+	cursorCreatedAt, cursorID := cursorPosition(cursor)
+
+	var dbSessions []database.Session
+	if walkingBack(cursor) {
+		dbSessions, err = cfg.db.ListSessionsAsc(r.Context(), database.ListSessionsAscParams{
+			UserID:          claims.UserID,
+			CursorCreatedAt: cursorCreatedAt,
+			CursorID:        cursorID,
+			RowLimit:        int32(limit + 1),
+		})
+	} else {
+		dbSessions, err = cfg.db.ListSessionsDesc(r.Context(), database.ListSessionsDescParams{
+			UserID:          claims.UserID,
+			CursorCreatedAt: cursorCreatedAt,
+			CursorID:        cursorID,
+			RowLimit:        int32(limit + 1),
+		})
+	}
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't get sessions", err)
+		return
+	}
+
+	dbSessions, nextCursor, prevCursor := pagination.Paginate(dbSessions, limit, cursor, func(s database.Session) pagination.Cursor {
+		return pagination.Cursor{CreatedAt: s.CreatedAt, ID: s.ID}
+	})
+
+	sessions := []Session{}
+	for _, dbSession := range dbSessions {
+		sessions = append(sessions, databaseSessionToSession(dbSession, claims.SessionID))
+	}
+
+	setLinkHeader(w, r, nextCursor, prevCursor)
+	respondWithJSON(w, http.StatusOK, response{
+		Sessions:   sessions,
+		NextCursor: nextCursor,
+		PrevCursor: prevCursor,
+	})
+}
+
+// handlerSessionsDelete logs out one of the caller's sessions. Its refresh
+// token and access JWTs stop working straight away.
+func (cfg *apiConfig) handlerSessionsDelete(w http.ResponseWriter, r *http.Request) {
+	sessionIDString := r.PathValue("sessionID")
+	sessionID, err := uuid.Parse(sessionIDString)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid session ID", err)
+		return
+	}
+
+	token, err := auth.GetBearerToken(r.Header)
+	if err != nil {
+		respondWithAuthError(w, err)
+		return
+	}
+	userID, err := cfg.validateJWT(r.Context(), token)
+	if err != nil {
+		respondWithAuthError(w, err)
+		return
+	}
+
+	revoked, err := cfg.db.RevokeSession(r.Context(), database.RevokeSessionParams{
+		ID:     sessionID,
+		UserID: userID,
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't revoke session", err)
+		return
+	}
+	if revoked == 0 {
+		respondWithError(w, http.StatusNotFound, "Couldn't find session", nil)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handlerSessionsDeleteOthers logs out everywhere except the session the
+// request was made with
+func (cfg *apiConfig) handlerSessionsDeleteOthers(w http.ResponseWriter, r *http.Request) {
+	token, err := auth.GetBearerToken(r.Header)
+	if err != nil {
+		respondWithAuthError(w, err)
+		return
+	}
+	claims, err := cfg.authenticate(r.Context(), token)
+	if err != nil {
+		respondWithAuthError(w, err)
+		return
+	}
+
+	_, err = cfg.db.RevokeOtherSessions(r.Context(), database.RevokeOtherSessionsParams{
+		UserID: claims.UserID,
+		ID:     claims.SessionID,
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't revoke sessions", err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,180 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/srinivassivaratri/Chirpy/internal/auth"
+	"github.com/srinivassivaratri/Chirpy/internal/database"
+	"github.com/srinivassivaratri/Chirpy/internal/entities"
+	"github.com/srinivassivaratri/Chirpy/internal/stream"
+)
+
+const (
+	// How often an idle stream sends a comment so proxies don't close it
+	streamHeartbeatInterval = 15 * time.Second
+	// A client that can't take a write in this long is disconnected
+	streamWriteTimeout = 10 * time.Second
+	// Most events sent to a client resuming with Last-Event-ID. One further
+	// behind gets a resync event instead.
+	streamMaxReplay = 1000
+	// How long clients wait before reconnecting, in milliseconds
+	streamRetry = 3000
+)
+
+func (cfg *apiConfig) handlerStream(w http.ResponseWriter, r *http.Request) {
+	viewerID, err := cfg.viewerID(r)
+	if err != nil {
+		respondWithAuthError(w, err)
+		return
+	}
+
+	filter := stream.Filter{}
+	if authorIDString := r.URL.Query().Get("author_id"); authorIDString != "" {
+		authorID, err := uuid.Parse(authorIDString)
+		if err != nil {
+			respondWithError(w, http.StatusBadRequest, "Invalid author ID", err)
+			return
+		}
+		filter.AuthorID = uuid.NullUUID{UUID: authorID, Valid: true}
+	}
+	filter.Hashtag = entities.NormalizeHashtag(r.URL.Query().Get("hashtag"))
+	filter.Hidden, err = cfg.hiddenUsers(r.Context(), viewerID)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't get blocked users", err)
+		return
+	}
+
+	// Only chirps from people the caller follows. Follows made after
+	// connecting show up once the client reconnects.
+	if r.URL.Query().Get("following") == "true" {
+		if !viewerID.Valid {
+			respondWithAuthError(w, auth.ErrNoAuthHeaderIncluded)
+			return
+		}
+		followeeIDs, err := cfg.db.ListFolloweeIDs(r.Context(), viewerID.UUID)
+		if err != nil {
+			respondWithError(w, http.StatusInternalServerError, "Couldn't get follows", err)
+			return
+		}
+		filter.Authors = map[uuid.UUID]bool{}
+		for _, id := range followeeIDs {
+			filter.Authors[id] = true
+		}
+	}
+
+	// Subscribe before reading the log so nothing published in between is missed
+	sub := cfg.chirpStream.Subscribe(filter)
+	defer sub.Close()
+
+	replay := []stream.Event{}
+	// Set when the client missed too much to replay. Events up to it are
+	// covered by the client refetching.
+	resyncID := int64(0)
+	if lastEventIDString := r.Header.Get("Last-Event-ID"); lastEventIDString != "" {
+		lastEventID, err := strconv.ParseInt(lastEventIDString, 10, 64)
+		if err != nil {
+			respondWithError(w, http.StatusBadRequest, "Invalid Last-Event-ID", err)
+			return
+		}
+		dbEvents, err := cfg.db.ListChirpEventsAfter(r.Context(), database.ListChirpEventsAfterParams{
+			AfterID:  lastEventID,
+			RowLimit: streamMaxReplay + 1,
+		})
+		if err != nil {
+			respondWithError(w, http.StatusInternalServerError, "Couldn't get missed events", err)
+			return
+		}
+		if len(dbEvents) > streamMaxReplay {
+			resyncID, err = cfg.db.GetLatestChirpEventID(r.Context())
+			if err != nil {
+				respondWithError(w, http.StatusInternalServerError, "Couldn't get missed events", err)
+				return
+			}
+		} else {
+			replay, err = cfg.chirpStreamEvents(r.Context(), dbEvents)
+			if err != nil {
+				respondWithError(w, http.StatusInternalServerError, "Couldn't get missed events", err)
+				return
+			}
+		}
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	rc := http.NewResponseController(w)
+
+	fmt.Fprintf(w, "retry: %d\n\n", streamRetry)
+	if resyncID > 0 {
+		// The client should reload GET /api/chirps. The id means it resumes
+		// from here if it reconnects.
+		err := writeStreamEvent(w, rc, stream.Event{ID: resyncID, Kind: "resync", Data: []byte("{}")})
+		if err != nil {
+			return
+		}
+	}
+	replayed := map[int64]bool{}
+	for _, e := range replay {
+		replayed[e.ID] = true
+		if !filter.Match(e) {
+			continue
+		}
+		if err := writeStreamEvent(w, rc, e); err != nil {
+			return
+		}
+	}
+	if err := flushStream(rc); err != nil {
+		return
+	}
+
+	heartbeat := time.NewTicker(streamHeartbeatInterval)
+	defer heartbeat.Stop()
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-sub.Dropped():
+			// The client fell behind. It reconnects with Last-Event-ID and
+			// catches up from the log.
+			return
+		case <-heartbeat.C:
+			fmt.Fprint(w, ": heartbeat\n\n")
+			if err := flushStream(rc); err != nil {
+				return
+			}
+		case e := <-sub.Events():
+			if replayed[e.ID] || e.ID <= resyncID {
+				continue
+			}
+			if err := writeStreamEvent(w, rc, e); err != nil {
+				return
+			}
+			if err := flushStream(rc); err != nil {
+				return
+			}
+		}
+	}
+}
+
+func writeStreamEvent(w http.ResponseWriter, rc *http.ResponseController, e stream.Event) error {
+	err := rc.SetWriteDeadline(time.Now().Add(streamWriteTimeout))
+	if err != nil && !errors.Is(err, http.ErrNotSupported) {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", e.ID, e.Kind, e.Data)
+	return err
+}
+
+func flushStream(rc *http.ResponseController) error {
+	err := rc.SetWriteDeadline(time.Now().Add(streamWriteTimeout))
+	if err != nil && !errors.Is(err, http.ErrNotSupported) {
+		return err
+	}
+	return rc.Flush()
+}
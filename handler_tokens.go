@@ -0,0 +1,43 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/srinivassivaratri/Chirpy/internal/database"
+)
+
+// handlerTokenDenylistCreate turns down an access JWT by its jti before it
+// expires, say because it leaked. It stays denied for as long as a token
+// issued now could be used.
+func (cfg *apiConfig) handlerTokenDenylistCreate(w http.ResponseWriter, r *http.Request) {
+	type parameters struct {
+		TokenID string `json:"jti"`
+	}
+
+	decoder := json.NewDecoder(r.Body)
+	params := parameters{}
+	err := decoder.Decode(&params)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't decode parameters", err)
+		return
+	}
+	tokenID := strings.TrimSpace(params.TokenID)
+	if tokenID == "" {
+		respondWithError(w, http.StatusBadRequest, "jti is required", nil)
+		return
+	}
+
+	err = cfg.db.DenyToken(r.Context(), database.DenyTokenParams{
+		Jti:       tokenID,
+		ExpiresAt: time.Now().UTC().Add(accessTokenLifetime + cfg.jwtConfig.Leeway),
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't deny token", err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -1,28 +1,37 @@
 package main
 
 import (
+	"database/sql"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"time"
 
 	"github.com/google/uuid"
-	"github.com/srinivassivaratri/Chirpy/internal/auth"
+	"github.com/lib/pq"
 	"github.com/srinivassivaratri/Chirpy/internal/database"
+	"github.com/srinivassivaratri/Chirpy/internal/entities"
 )
 
+var errInvalidUsername = errors.New("Username must be 1 to 15 letters, numbers or underscores")
+
 type User struct {
-	ID          uuid.UUID `json:"id"`            // A unique identifier for each user, like a fingerprint. Uses UUID (Universally Unique ID) to avoid duplicates
-	CreatedAt   time.Time `json:"created_at"`    // Records when the user first signed up, like a birth certificate date
-	UpdatedAt   time.Time `json:"updated_at"`    // Tracks when user info was last changed, like updating your driver's license
-	Email       string    `json:"email"`         // User's email address for login and contact, like a digital mailbox
-	Password    string    `json:"-"`             // User's password, hidden from JSON output (that's what "-" means) for security
-	IsChirpyRed bool      `json:"is_chirpy_red"` // Whether user has premium features (true) or free account (false), like a VIP pass
+	ID          uuid.UUID `json:"id"`                 // A unique identifier for each user, like a fingerprint. Uses UUID (Universally Unique ID) to avoid duplicates
+	CreatedAt   time.Time `json:"created_at"`         // Records when the user first signed up, like a birth certificate date
+	UpdatedAt   time.Time `json:"updated_at"`         // Tracks when user info was last changed, like updating your driver's license
+	Email       string    `json:"email"`              // User's email address for login and contact, like a digital mailbox
+	Password    string    `json:"-"`                  // User's password, hidden from JSON output (that's what "-" means) for security
+	IsChirpyRed bool      `json:"is_chirpy_red"`      // Whether user has premium features (true) or free account (false), like a VIP pass
+	Username    string    `json:"username,omitempty"` // Public handle other users can @mention, unique ignoring case. Optional, like a nickname
+	DMPolicy    string    `json:"dm_policy"`          // Who can start a direct message conversation with the user: everyone, following or nobody
+	Role        string    `json:"role"`               // What the user is allowed to do: user, moderator or admin, like the keys on a janitor's ring
 }
 
 func (cfg *apiConfig) handlerUsersCreate(w http.ResponseWriter, r *http.Request) {
 	type parameters struct {
 		Password string `json:"password"`
 		Email    string `json:"email"`
+		Username string `json:"username"`
 	}
 	type response struct {
 		User
@@ -36,7 +45,16 @@ func (cfg *apiConfig) handlerUsersCreate(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	hashedPassword, err := auth.HashPassword(params.Password)
+	username := sql.NullString{}
+	if params.Username != "" {
+		if !entities.ValidUsername(params.Username) {
+			respondWithError(w, http.StatusBadRequest, errInvalidUsername.Error(), nil)
+			return
+		}
+		username = sql.NullString{String: params.Username, Valid: true}
+	}
+
+	hashedPassword, err := cfg.passwords.Hash(params.Password)
 	if err != nil {
 		respondWithError(w, http.StatusInternalServerError, "Couldn't hash password", err)
 		return
@@ -45,7 +63,16 @@ func (cfg *apiConfig) handlerUsersCreate(w http.ResponseWriter, r *http.Request)
 	user, err := cfg.db.CreateUser(r.Context(), database.CreateUserParams{
 		Email:          params.Email,
 		HashedPassword: hashedPassword,
+		Username:       username,
 	})
+	if violatesConstraint(err, usernameConstraint) {
+		respondWithError(w, http.StatusConflict, "Username is already taken", err)
+		return
+	}
+	if violatesConstraint(err, emailConstraint) {
+		respondWithError(w, http.StatusConflict, "Email is already taken", err)
+		return
+	}
 	if err != nil {
 		respondWithError(w, http.StatusInternalServerError, "Couldn't create user", err)
 		return
@@ -53,11 +80,32 @@ func (cfg *apiConfig) handlerUsersCreate(w http.ResponseWriter, r *http.Request)
 
 	respondWithJSON(w, http.StatusCreated, response{
 		User: User{
-			ID:          user.ID,          // Copies the user's unique ID number (like a digital fingerprint) from the database to send back
-			CreatedAt:   user.CreatedAt,   // Copies the timestamp of when user first signed up from database to send back
-			UpdatedAt:   user.UpdatedAt,   // Copies the timestamp of user's last info update from database to send back
-			Email:       user.Email,       // Copies the user's email address from database to send back
-			IsChirpyRed: user.IsChirpyRed, // Copies whether user has premium features (true/false) from database to send back
+			ID:          user.ID,              // Copies the user's unique ID number (like a digital fingerprint) from the database to send back
+			CreatedAt:   user.CreatedAt,       // Copies the timestamp of when user first signed up from database to send back
+			UpdatedAt:   user.UpdatedAt,       // Copies the timestamp of user's last info update from database to send back
+			Email:       user.Email,           // Copies the user's email address from database to send back
+			Username:    user.Username.String, // Copies the user's public handle from database to send back, empty if they haven't picked one
+			IsChirpyRed: user.IsChirpyRed,     // Copies whether user has premium features (true/false) from database to send back
+			DMPolicy:    user.DmPolicy,        // Copies who can message the user from database to send back, "everyone" for new users
+			Role:        user.Role,            // Copies what the user is allowed to do from database to send back, "user" unless they're the first admin
 		},
 	})
 }
+
+// Unique constraints on users, named so a conflict can say which one it hit
+const (
+	usernameConstraint = "users_username_idx"
+	emailConstraint    = "users_email_key"
+)
+
+func isUniqueViolation(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == "23505"
+}
+
+// violatesConstraint reports whether err is a unique violation of the named
+// constraint or index
+func violatesConstraint(err error, constraint string) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == "23505" && pqErr.Constraint == constraint
+}
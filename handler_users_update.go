@@ -1,17 +1,21 @@
 package main
 
 import (
+	"database/sql"
 	"encoding/json"
 	"net/http"
 
 	"github.com/srinivassivaratri/Chirpy/internal/auth"
 	"github.com/srinivassivaratri/Chirpy/internal/database"
+	"github.com/srinivassivaratri/Chirpy/internal/entities"
 )
 
 func (cfg *apiConfig) handlerUsersUpdate(w http.ResponseWriter, r *http.Request) {
 	type parameters struct {
 		Password string `json:"password"`
 		Email    string `json:"email"`
+		Username string `json:"username"`
+		DMPolicy string `json:"dm_policy"`
 	}
 	type response struct {
 		User
@@ -19,12 +23,12 @@ func (cfg *apiConfig) handlerUsersUpdate(w http.ResponseWriter, r *http.Request)
 
 	token, err := auth.GetBearerToken(r.Header)
 	if err != nil {
-		respondWithError(w, http.StatusUnauthorized, "Couldn't find JWT", err)
+		respondWithAuthError(w, err)
 		return
 	}
-	userID, err := auth.ValidateJWT(token, cfg.jwtSecret)
+	userID, err := cfg.validateJWT(r.Context(), token)
 	if err != nil {
-		respondWithError(w, http.StatusUnauthorized, "Couldn't validate JWT", err)
+		respondWithAuthError(w, err)
 		return
 	}
 
@@ -36,7 +40,26 @@ func (cfg *apiConfig) handlerUsersUpdate(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	hashedPassword, err := auth.HashPassword(params.Password)
+	// Leaving username out keeps the current one
+	username := sql.NullString{}
+	if params.Username != "" {
+		if !entities.ValidUsername(params.Username) {
+			respondWithError(w, http.StatusBadRequest, errInvalidUsername.Error(), nil)
+			return
+		}
+		username = sql.NullString{String: params.Username, Valid: true}
+	}
+
+	dmPolicy := sql.NullString{}
+	if params.DMPolicy != "" {
+		if !validDMPolicy(params.DMPolicy) {
+			respondWithError(w, http.StatusBadRequest, errInvalidDMPolicy.Error(), nil)
+			return
+		}
+		dmPolicy = sql.NullString{String: params.DMPolicy, Valid: true}
+	}
+
+	hashedPassword, err := cfg.passwords.Hash(params.Password)
 	if err != nil {
 		respondWithError(w, http.StatusInternalServerError, "Couldn't hash password", err)
 		return
@@ -46,7 +69,17 @@ func (cfg *apiConfig) handlerUsersUpdate(w http.ResponseWriter, r *http.Request)
 		ID:             userID,
 		Email:          params.Email,
 		HashedPassword: hashedPassword,
+		Username:       username,
+		DmPolicy:       dmPolicy,
 	})
+	if violatesConstraint(err, usernameConstraint) {
+		respondWithError(w, http.StatusConflict, "Username is already taken", err)
+		return
+	}
+	if violatesConstraint(err, emailConstraint) {
+		respondWithError(w, http.StatusConflict, "Email is already taken", err)
+		return
+	}
 	if err != nil {
 		respondWithError(w, http.StatusInternalServerError, "Couldn't update user", err)
 		return
@@ -54,11 +87,14 @@ func (cfg *apiConfig) handlerUsersUpdate(w http.ResponseWriter, r *http.Request)
 
 	respondWithJSON(w, http.StatusOK, response{
 		User: User{
-			ID:          user.ID,          // Copies the user's unique ID number (like a digital fingerprint) from the database to send back
-			CreatedAt:   user.CreatedAt,   // Copies the timestamp of when user first signed up from database to send back
-			UpdatedAt:   user.UpdatedAt,   // Copies the timestamp of user's last info update from database to send back
-			Email:       user.Email,       // Copies the user's email address from database to send back
-			IsChirpyRed: user.IsChirpyRed, // Copies whether user has premium features (true/false) from database to send back
+			ID:          user.ID,              // Copies the user's unique ID number (like a digital fingerprint) from the database to send back
+			CreatedAt:   user.CreatedAt,       // Copies the timestamp of when user first signed up from database to send back
+			UpdatedAt:   user.UpdatedAt,       // Copies the timestamp of user's last info update from database to send back
+			Email:       user.Email,           // Copies the user's email address from database to send back
+			Username:    user.Username.String, // Copies the user's public handle from database to send back, empty if they haven't picked one
+			IsChirpyRed: user.IsChirpyRed,     // Copies whether user has premium features (true/false) from database to send back
+			DMPolicy:    user.DmPolicy,        // Copies who can message the user from database to send back
+			Role:        user.Role,            // Copies what the user is allowed to do from database to send back
 		},
 	})
 }
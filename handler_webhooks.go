@@ -8,6 +8,7 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/srinivassivaratri/Chirpy/internal/auth"
+	"github.com/srinivassivaratri/Chirpy/internal/notifications"
 )
 
 // This function is created as part of the apiConfig struct and handles incoming webhook messages from Polka payment service
@@ -71,6 +72,13 @@ func (cfg *apiConfig) handlerWebhook(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Let the user know their membership is active. This is saved in the
+	// background so Polka gets its answer right away
+	cfg.notifier.Notify(notifications.Notification{
+		UserID: params.Data.UserID,
+		Kind:   notifications.KindChirpyRed,
+	})
+
 	// If everything worked perfectly, send back a simple "ok, done" response
 	w.WriteHeader(http.StatusNoContent)
 }
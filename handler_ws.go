@@ -0,0 +1,412 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/websocket"
+	"github.com/srinivassivaratri/Chirpy/internal/auth"
+	"github.com/srinivassivaratri/Chirpy/internal/database"
+	"github.com/srinivassivaratri/Chirpy/internal/entities"
+	"github.com/srinivassivaratri/Chirpy/internal/notifications"
+	"github.com/srinivassivaratri/Chirpy/internal/pubsub"
+	"github.com/srinivassivaratri/Chirpy/internal/stream"
+)
+
+const (
+	// A write that takes longer than this drops the connection
+	wsWriteTimeout = 10 * time.Second
+	// A client that doesn't answer a ping in this long is dropped
+	wsPongTimeout = 60 * time.Second
+	// Pings go out often enough that a healthy client answers in time
+	wsPingInterval = wsPongTimeout * 9 / 10
+	// Messages waiting to be written. A client that falls this far behind is
+	// disconnected and can reconnect and subscribe again.
+	wsSendBuffer = 64
+	// Most channels one connection can subscribe to
+	wsMaxSubscriptions = 10
+	// Largest message a client can send, in bytes
+	wsMaxMessageSize = 4096
+	// How often a logged in connection checks its session hasn't been
+	// revoked
+	wsSessionCheckInterval = 30 * time.Second
+)
+
+var wsUpgrader = websocket.Upgrader{}
+
+// wsClientMessage is a message from the client. Type is auth, subscribe,
+// unsubscribe or ping.
+type wsClientMessage struct {
+	Type    string `json:"type"`
+	Channel string `json:"channel"`
+	Token   string `json:"token"`
+}
+
+// wsServerMessage is a message to the client. Type is authenticated,
+// subscribed, unsubscribed, event, resync, pong or error.
+type wsServerMessage struct {
+	Type    string          `json:"type"`
+	Channel string          `json:"channel,omitempty"`
+	Event   string          `json:"event,omitempty"`
+	ID      int64           `json:"id,omitempty"`
+	Data    json.RawMessage `json:"data,omitempty"`
+	Error   string          `json:"error,omitempty"`
+	// final closes the connection once the message is written
+	final bool
+}
+
+// errWSInternal hides the details of a server-side failure from the client
+var errWSInternal = errors.New("Couldn't subscribe")
+
+type wsConn struct {
+	cfg    *apiConfig
+	conn   *websocket.Conn
+	userID uuid.NullUUID
+	send   chan wsServerMessage
+	ctx    context.Context
+	cancel context.CancelFunc
+	// subscriptions maps each channel to the function that stops it. Only
+	// the read loop uses it.
+	subscriptions map[string]context.CancelFunc
+}
+
+func (cfg *apiConfig) handlerWebSocket(w http.ResponseWriter, r *http.Request) {
+	// Browsers can't set headers on a WebSocket, so they log in with an auth
+	// message once connected instead
+	var claims *auth.Claims
+	token, err := auth.GetBearerToken(r.Header)
+	if err != nil && !errors.Is(err, auth.ErrNoAuthHeaderIncluded) {
+		respondWithAuthError(w, err)
+		return
+	}
+	if err == nil {
+		parsed, err := cfg.authenticate(r.Context(), token)
+		if err != nil {
+			respondWithAuthError(w, err)
+			return
+		}
+		claims = &parsed
+	}
+
+	conn, err := wsUpgrader.Upgrade(w, r, nil)
+	if err != nil {
+		// Upgrade has already responded
+		log.Printf("Error upgrading to WebSocket: %s", err)
+		return
+	}
+
+	ctx, cancel := context.WithCancel(r.Context())
+	c := &wsConn{
+		cfg:           cfg,
+		conn:          conn,
+		send:          make(chan wsServerMessage, wsSendBuffer),
+		ctx:           ctx,
+		cancel:        cancel,
+		subscriptions: map[string]context.CancelFunc{},
+	}
+	defer cancel()
+	if claims != nil {
+		c.logIn(*claims)
+	}
+
+	go c.writeLoop()
+	c.readLoop()
+}
+
+// queue hands m to the write loop without waiting. A client that has fallen
+// too far behind is disconnected.
+func (c *wsConn) queue(m wsServerMessage) {
+	select {
+	case c.send <- m:
+	default:
+		c.cancel()
+	}
+}
+
+func (c *wsConn) queueError(channel string, err error) {
+	c.queue(wsServerMessage{Type: "error", Channel: channel, Error: err.Error()})
+}
+
+// writeLoop is the only place that writes to the connection. It closes the
+// connection once the context is done, which also ends the read loop.
+func (c *wsConn) writeLoop() {
+	ticker := time.NewTicker(wsPingInterval)
+	defer ticker.Stop()
+	defer c.conn.Close()
+	for {
+		select {
+		case <-c.ctx.Done():
+			return
+		case m := <-c.send:
+			c.conn.SetWriteDeadline(time.Now().Add(wsWriteTimeout))
+			err := c.conn.WriteJSON(m)
+			if err != nil || m.final {
+				c.cancel()
+				return
+			}
+		case <-ticker.C:
+			err := c.conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(wsWriteTimeout))
+			if err != nil {
+				c.cancel()
+				return
+			}
+		}
+	}
+}
+
+func (c *wsConn) readLoop() {
+	c.conn.SetReadLimit(wsMaxMessageSize)
+	c.conn.SetReadDeadline(time.Now().Add(wsPongTimeout))
+	c.conn.SetPongHandler(func(string) error {
+		return c.conn.SetReadDeadline(time.Now().Add(wsPongTimeout))
+	})
+
+	for {
+		_, dat, err := c.conn.ReadMessage()
+		if err != nil {
+			return
+		}
+		msg := wsClientMessage{}
+		err = json.Unmarshal(dat, &msg)
+		if err != nil {
+			c.queueError("", errors.New("Couldn't decode message"))
+			continue
+		}
+
+		switch msg.Type {
+		case "auth":
+			c.authenticate(msg.Token)
+		case "subscribe":
+			c.subscribe(msg.Channel)
+		case "unsubscribe":
+			c.unsubscribe(msg.Channel)
+		case "ping":
+			c.queue(wsServerMessage{Type: "pong"})
+		default:
+			c.queueError("", errors.New("Unknown message type"))
+		}
+	}
+}
+
+func (c *wsConn) authenticate(token string) {
+	if c.userID.Valid {
+		c.queueError("", errors.New("Already logged in"))
+		return
+	}
+	claims, err := c.cfg.authenticate(c.ctx, token)
+	if err != nil {
+		c.queueError("", errors.New("Couldn't validate JWT"))
+		return
+	}
+	c.logIn(claims)
+	c.queue(wsServerMessage{Type: "authenticated"})
+}
+
+// logIn makes the connection the user's for as long as their session lasts
+func (c *wsConn) logIn(claims auth.Claims) {
+	c.userID = uuid.NullUUID{UUID: claims.UserID, Valid: true}
+	go c.watchSession(claims)
+}
+
+// watchSession disconnects a logged in connection once its session is
+// revoked, so it stops getting the user's notifications. The check is
+// against the database, so it works whichever instance did the revoking.
+func (c *wsConn) watchSession(claims auth.Claims) {
+	ticker := time.NewTicker(wsSessionCheckInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-c.ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		active, err := c.cfg.db.IsSessionActive(c.ctx, database.IsSessionActiveParams{
+			ID:     claims.SessionID,
+			UserID: claims.UserID,
+		})
+		if err != nil {
+			if c.ctx.Err() == nil {
+				log.Printf("Error checking session %s: %s", claims.SessionID, err)
+			}
+			continue
+		}
+		if !active {
+			c.queue(wsServerMessage{Type: "error", Error: "Session has been revoked", final: true})
+			return
+		}
+	}
+}
+
+func (c *wsConn) unsubscribe(channel string) {
+	stop, ok := c.subscriptions[channel]
+	if !ok {
+		c.queueError(channel, errors.New("Not subscribed"))
+		return
+	}
+	stop()
+	delete(c.subscriptions, channel)
+	c.queue(wsServerMessage{Type: "unsubscribed", Channel: channel})
+}
+
+// subscribe starts pushing events for channel, which is one of timeline,
+// thread:<chirpID>, hashtag:<tag> or notifications
+func (c *wsConn) subscribe(channel string) {
+	if _, ok := c.subscriptions[channel]; ok {
+		c.queue(wsServerMessage{Type: "subscribed", Channel: channel})
+		return
+	}
+	if len(c.subscriptions) >= wsMaxSubscriptions {
+		c.queueError(channel, errors.New("Too many subscriptions"))
+		return
+	}
+
+	ctx, stop := context.WithCancel(c.ctx)
+	err := c.startSubscription(ctx, channel)
+	if err != nil {
+		stop()
+		c.queueError(channel, err)
+		return
+	}
+	c.subscriptions[channel] = stop
+	c.queue(wsServerMessage{Type: "subscribed", Channel: channel})
+}
+
+func (c *wsConn) startSubscription(ctx context.Context, channel string) error {
+	// Blocks and mutes made after subscribing apply on the next subscribe
+	hidden, err := c.cfg.hiddenUsers(ctx, c.userID)
+	if err != nil {
+		log.Printf("Error getting blocked users: %s", err)
+		return errWSInternal
+	}
+
+	kind, arg, _ := strings.Cut(channel, ":")
+	switch kind {
+	case "timeline":
+		// Chirps from people the user follows, as of subscribing
+		if !c.userID.Valid {
+			return errors.New("Log in to subscribe to your timeline")
+		}
+		followeeIDs, err := c.cfg.db.ListFolloweeIDs(ctx, c.userID.UUID)
+		if err != nil {
+			log.Printf("Error getting follows: %s", err)
+			return errWSInternal
+		}
+		filter := stream.Filter{Authors: map[uuid.UUID]bool{}, Hidden: hidden}
+		for _, id := range followeeIDs {
+			filter.Authors[id] = true
+		}
+		go c.forwardChirps(ctx, channel, c.cfg.chirpStream.Subscribe(filter))
+		return nil
+
+	case "thread":
+		chirpID, err := uuid.Parse(arg)
+		if err != nil {
+			return errors.New("Invalid chirp ID")
+		}
+		dbChirp, err := c.cfg.db.GetChirp(ctx, chirpID)
+		if errors.Is(err, sql.ErrNoRows) {
+			return errors.New("Couldn't find chirp")
+		}
+		if err != nil {
+			log.Printf("Error getting chirp: %s", err)
+			return errWSInternal
+		}
+		filter := stream.Filter{ThreadID: uuid.NullUUID{UUID: dbChirp.ThreadID, Valid: true}, Hidden: hidden}
+		go c.forwardChirps(ctx, channel, c.cfg.chirpStream.Subscribe(filter))
+		return nil
+
+	case "hashtag":
+		tag := entities.NormalizeHashtag(arg)
+		if tag == "" {
+			return errors.New("Invalid hashtag")
+		}
+		go c.forwardChirps(ctx, channel, c.cfg.chirpStream.Subscribe(stream.Filter{Hashtag: tag, Hidden: hidden}))
+		return nil
+
+	case "notifications":
+		if !c.userID.Valid {
+			return errors.New("Log in to subscribe to notifications")
+		}
+		sub, err := c.cfg.pubsub.Subscribe(notifications.Channel)
+		if err != nil {
+			log.Printf("Error subscribing to notifications: %s", err)
+			return errWSInternal
+		}
+		go c.forwardNotifications(ctx, channel, c.userID.UUID, sub)
+		return nil
+	}
+	return errors.New("Unknown channel")
+}
+
+func (c *wsConn) forwardChirps(ctx context.Context, channel string, sub *stream.Subscription) {
+	defer sub.Close()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-sub.Dropped():
+			// Too far behind, same as a full send buffer
+			c.cancel()
+			return
+		case e := <-sub.Events():
+			c.queue(wsServerMessage{
+				Type:    "event",
+				Channel: channel,
+				Event:   e.Kind,
+				ID:      e.ID,
+				Data:    json.RawMessage(e.Data),
+			})
+		}
+	}
+}
+
+func (c *wsConn) forwardNotifications(ctx context.Context, channel string, userID uuid.UUID, sub *pubsub.Subscription) {
+	defer sub.Close()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case m := <-sub.Messages():
+			// Some notifications may have been missed, the client should
+			// reload GET /api/notifications
+			if m.Resync {
+				c.queue(wsServerMessage{Type: "resync", Channel: channel})
+				continue
+			}
+			announcement := notifications.Announcement{}
+			err := json.Unmarshal([]byte(m.Payload), &announcement)
+			if err != nil {
+				log.Printf("Error decoding notification announcement: %s", err)
+				continue
+			}
+			if announcement.UserID != userID {
+				continue
+			}
+
+			dbNotification, err := c.cfg.db.GetNotification(ctx, announcement.ID)
+			if err != nil {
+				log.Printf("Error getting notification %s: %s", announcement.ID, err)
+				continue
+			}
+			data, err := json.Marshal(databaseNotificationToNotification(dbNotification))
+			if err != nil {
+				log.Printf("Error encoding notification %s: %s", announcement.ID, err)
+				continue
+			}
+			c.queue(wsServerMessage{
+				Type:    "event",
+				Channel: channel,
+				Event:   "notification",
+				Data:    data,
+			})
+		}
+	}
+}
@@ -1,6 +1,7 @@
 package auth
 
 import (
+	"context"
 	"crypto/rand"
 	"encoding/hex"
 	"errors"
@@ -11,7 +12,6 @@ import (
 
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/google/uuid"
-	"golang.org/x/crypto/bcrypt"
 )
 
 type TokenType string
@@ -24,66 +24,166 @@ const (
 // ErrNoAuthHeaderIncluded -
 var ErrNoAuthHeaderIncluded = errors.New("no auth header included in request")
 
-// HashPassword -
-func HashPassword(password string) (string, error) {
-	dat, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
-	if err != nil {
-		return "", err
+// ErrMalformedAuthHeader -
+var ErrMalformedAuthHeader = errors.New("malformed authorization header")
+
+// Errors from ParseJWT say why a token was turned down, so callers can
+// tell the client
+var (
+	ErrMalformedToken   = errors.New("token is malformed")
+	ErrBadSignature     = errors.New("token signature is invalid")
+	ErrTokenExpired     = errors.New("token has expired")
+	ErrTokenNotYetValid = errors.New("token isn't valid yet")
+	ErrWrongIssuer      = errors.New("token has the wrong issuer")
+	ErrWrongAudience    = errors.New("token is for a different audience")
+	ErrTokenRevoked     = errors.New("token has been revoked")
+)
+
+// Denylist rejects access JWTs by jti before they expire
+type Denylist interface {
+	Denied(ctx context.Context, tokenID string) (bool, error)
+}
+
+// JWTConfig says how access JWTs are signed and what ParseJWT accepts
+type JWTConfig struct {
+	Keys *Keyring
+	// Issuer goes in "iss" and has to match. Defaults to TokenTypeAccess.
+	Issuer string
+	// Audience goes in "aud" and has to be there, if set
+	Audience string
+	// Leeway allows for clocks that disagree when checking exp, nbf and iat
+	Leeway time.Duration
+	// Denylist is optional
+	Denylist Denylist
+}
+
+func (c JWTConfig) issuer() string {
+	if c.Issuer == "" {
+		return string(TokenTypeAccess)
 	}
-	return string(dat), nil
+	return c.Issuer
+}
+
+// Claims is who an access JWT was issued to, the login session it belongs to
+// and its own ID
+type Claims struct {
+	UserID    uuid.UUID
+	SessionID uuid.UUID
+	TokenID   string
+	ExpiresAt time.Time
 }
 
-// CheckPasswordHash -
-func CheckPasswordHash(password, hash string) error {
-	return bcrypt.CompareHashAndPassword([]byte(hash), []byte(password))
+type accessClaims struct {
+	jwt.RegisteredClaims
+	SessionID string `json:"sid"`
 }
 
 // MakeJWT -
 func MakeJWT(
 	userID uuid.UUID,
-	tokenSecret string,
+	sessionID uuid.UUID,
+	config JWTConfig,
 	expiresIn time.Duration,
 ) (string, error) {
-	signingKey := []byte(tokenSecret)
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.RegisteredClaims{
-		Issuer:    string(TokenTypeAccess),
-		IssuedAt:  jwt.NewNumericDate(time.Now().UTC()),
-		ExpiresAt: jwt.NewNumericDate(time.Now().UTC().Add(expiresIn)),
-		Subject:   userID.String(),
-	})
-	return token.SignedString(signingKey)
+	now := time.Now().UTC()
+	claims := accessClaims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        uuid.New().String(),
+			Issuer:    config.issuer(),
+			IssuedAt:  jwt.NewNumericDate(now),
+			NotBefore: jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(expiresIn)),
+			Subject:   userID.String(),
+		},
+		SessionID: sessionID.String(),
+	}
+	if config.Audience != "" {
+		claims.Audience = jwt.ClaimStrings{config.Audience}
+	}
+	return config.Keys.Sign(claims)
 }
 
 // ValidateJWT -
-func ValidateJWT(tokenString, tokenSecret string) (uuid.UUID, error) {
-	claimsStruct := jwt.RegisteredClaims{}
-	token, err := jwt.ParseWithClaims(
-		tokenString,
-		&claimsStruct,
-		func(token *jwt.Token) (interface{}, error) { return []byte(tokenSecret), nil },
-	)
+func ValidateJWT(ctx context.Context, tokenString string, config JWTConfig) (uuid.UUID, error) {
+	claims, err := ParseJWT(ctx, tokenString, config)
 	if err != nil {
 		return uuid.Nil, err
 	}
+	return claims.UserID, nil
+}
+
+// ParseJWT validates an access JWT against the key its kid names and returns
+// its claims. Only the algorithms the keyring signs with are accepted. It
+// doesn't know whether the session has since been revoked.
+func ParseJWT(ctx context.Context, tokenString string, config JWTConfig) (Claims, error) {
+	options := []jwt.ParserOption{
+		jwt.WithValidMethods(config.Keys.algorithms()),
+		jwt.WithIssuer(config.issuer()),
+		jwt.WithLeeway(config.Leeway),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuedAt(),
+	}
+	if config.Audience != "" {
+		options = append(options, jwt.WithAudience(config.Audience))
+	}
+	parser := jwt.NewParser(options...)
 
-	userIDString, err := token.Claims.GetSubject()
+	claimsStruct := accessClaims{}
+	_, err := parser.ParseWithClaims(tokenString, &claimsStruct, config.Keys.verificationKey)
 	if err != nil {
-		return uuid.Nil, err
+		return Claims{}, classifyJWTError(err)
 	}
 
-	issuer, err := token.Claims.GetIssuer()
+	id, err := uuid.Parse(claimsStruct.Subject)
 	if err != nil {
-		return uuid.Nil, err
+		return Claims{}, fmt.Errorf("%w: invalid user ID: %w", ErrMalformedToken, err)
+	}
+	sessionID, err := uuid.Parse(claimsStruct.SessionID)
+	if err != nil {
+		return Claims{}, fmt.Errorf("%w: invalid session ID: %w", ErrMalformedToken, err)
 	}
-	if issuer != string(TokenTypeAccess) {
-		return uuid.Nil, errors.New("invalid issuer")
+	if claimsStruct.ID == "" {
+		return Claims{}, fmt.Errorf("%w: no token ID", ErrMalformedToken)
 	}
 
-	id, err := uuid.Parse(userIDString)
-	if err != nil {
-		return uuid.Nil, fmt.Errorf("invalid user ID: %w", err)
+	if config.Denylist != nil {
+		denied, err := config.Denylist.Denied(ctx, claimsStruct.ID)
+		if err != nil {
+			return Claims{}, err
+		}
+		if denied {
+			return Claims{}, ErrTokenRevoked
+		}
+	}
+	return Claims{
+		UserID:    id,
+		SessionID: sessionID,
+		TokenID:   claimsStruct.ID,
+		ExpiresAt: claimsStruct.ExpiresAt.Time,
+	}, nil
+}
+
+// classifyJWTError turns the jwt package's errors into ours, keeping the
+// original wrapped for logs
+func classifyJWTError(err error) error {
+	kinds := []struct {
+		jwtErr error
+		ours   error
+	}{
+		{jwt.ErrTokenExpired, ErrTokenExpired},
+		{jwt.ErrTokenNotValidYet, ErrTokenNotYetValid},
+		{jwt.ErrTokenUsedBeforeIssued, ErrTokenNotYetValid},
+		{jwt.ErrTokenInvalidIssuer, ErrWrongIssuer},
+		{jwt.ErrTokenInvalidAudience, ErrWrongAudience},
+		{jwt.ErrTokenSignatureInvalid, ErrBadSignature},
+		{jwt.ErrTokenUnverifiable, ErrBadSignature},
+	}
+	for _, kind := range kinds {
+		if errors.Is(err, kind.jwtErr) {
+			return fmt.Errorf("%w: %w", kind.ours, err)
+		}
 	}
-	return id, nil
+	return fmt.Errorf("%w: %w", ErrMalformedToken, err)
 }
 
 // GetBearerToken -
@@ -94,7 +194,7 @@ func GetBearerToken(headers http.Header) (string, error) {
 	}
 	splitAuth := strings.Split(authHeader, " ")
 	if len(splitAuth) < 2 || splitAuth[0] != "Bearer" {
-		return "", errors.New("malformed authorization header")
+		return "", ErrMalformedAuthHeader
 	}
 
 	return splitAuth[1], nil
@@ -1,10 +1,14 @@
 package auth
 
 import (
+	"context"
+	"errors"
 	"net/http"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/golang-jwt/jwt/v5"
 	"github.com/google/uuid"
 )
 
@@ -65,33 +69,34 @@ func TestCheckPasswordHash(t *testing.T) {
 
 func TestValidateJWT(t *testing.T) {
 	userID := uuid.New()
-	validToken, _ := MakeJWT(userID, "secret", time.Hour)
+	secretConfig := JWTConfig{Keys: secretKeyring(t, "secret")}
+	validToken, _ := MakeJWT(userID, uuid.New(), secretConfig, time.Hour)
 
 	tests := []struct {
 		name        string
 		tokenString string
-		tokenSecret string
+		config      JWTConfig
 		wantUserID  uuid.UUID
 		wantErr     bool
 	}{
 		{
 			name:        "Valid token",
 			tokenString: validToken,
-			tokenSecret: "secret",
+			config:      secretConfig,
 			wantUserID:  userID,
 			wantErr:     false,
 		},
 		{
 			name:        "Invalid token",
 			tokenString: "invalid.token.string",
-			tokenSecret: "secret",
+			config:      secretConfig,
 			wantUserID:  uuid.Nil,
 			wantErr:     true,
 		},
 		{
 			name:        "Wrong secret",
 			tokenString: validToken,
-			tokenSecret: "wrong_secret",
+			config:      JWTConfig{Keys: secretKeyring(t, "wrong_secret")},
 			wantUserID:  uuid.Nil,
 			wantErr:     true,
 		},
@@ -99,7 +104,7 @@ func TestValidateJWT(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			gotUserID, err := ValidateJWT(tt.tokenString, tt.tokenSecret)
+			gotUserID, err := ValidateJWT(context.Background(), tt.tokenString, tt.config)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("ValidateJWT() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -111,6 +116,99 @@ func TestValidateJWT(t *testing.T) {
 	}
 }
 
+func TestParseJWT(t *testing.T) {
+	userID := uuid.New()
+	sessionID := uuid.New()
+	config := JWTConfig{
+		Keys:     secretKeyring(t, "secret"),
+		Issuer:   "chirpy",
+		Audience: "chirpy-api",
+		Leeway:   30 * time.Second,
+		Denylist: denylist{"denied-id": true},
+	}
+	token := func(config JWTConfig, expiresIn time.Duration) string {
+		t.Helper()
+		s, err := MakeJWT(userID, sessionID, config, expiresIn)
+		if err != nil {
+			t.Fatalf("MakeJWT() error = %v", err)
+		}
+		return s
+	}
+	signed := func(claims accessClaims) string {
+		t.Helper()
+		s, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte("secret"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return s
+	}
+	registered := func(id string) jwt.RegisteredClaims {
+		return jwt.RegisteredClaims{
+			ID:        id,
+			Issuer:    "chirpy",
+			Audience:  jwt.ClaimStrings{"chirpy-api"},
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+			Subject:   userID.String(),
+		}
+	}
+	otherIssuer := config
+	otherIssuer.Issuer = "someone-else"
+	otherAudience := config
+	otherAudience.Audience = "another-api"
+	otherSecret := config
+	otherSecret.Keys = secretKeyring(t, "wrong_secret")
+
+	tests := []struct {
+		name        string
+		tokenString string
+		wantErr     error
+	}{
+		{name: "Valid token", tokenString: token(config, time.Hour)},
+		{name: "Expired within leeway", tokenString: token(config, -10*time.Second)},
+		{name: "Expired", tokenString: token(config, -time.Minute), wantErr: ErrTokenExpired},
+		{name: "Wrong issuer", tokenString: token(otherIssuer, time.Hour), wantErr: ErrWrongIssuer},
+		{name: "Wrong audience", tokenString: token(otherAudience, time.Hour), wantErr: ErrWrongAudience},
+		{name: "Wrong secret", tokenString: token(otherSecret, time.Hour), wantErr: ErrBadSignature},
+		{name: "Garbage", tokenString: "invalid.token.string", wantErr: ErrMalformedToken},
+		{
+			name:        "Unsigned",
+			tokenString: "eyJhbGciOiJub25lIiwidHlwIjoiSldUIn0." + strings.Split(token(config, time.Hour), ".")[1] + ".",
+			wantErr:     ErrBadSignature,
+		},
+		{
+			name:        "No session",
+			tokenString: signed(accessClaims{RegisteredClaims: registered("some-id")}),
+			wantErr:     ErrMalformedToken,
+		},
+		{
+			name:        "No token ID",
+			tokenString: signed(accessClaims{RegisteredClaims: registered(""), SessionID: sessionID.String()}),
+			wantErr:     ErrMalformedToken,
+		},
+		{
+			name:        "Denied",
+			tokenString: signed(accessClaims{RegisteredClaims: registered("denied-id"), SessionID: sessionID.String()}),
+			wantErr:     ErrTokenRevoked,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseJWT(context.Background(), tt.tokenString, config)
+			if !errors.Is(err, tt.wantErr) || (err != nil) != (tt.wantErr != nil) {
+				t.Fatalf("ParseJWT() error = %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if got.UserID != userID || got.SessionID != sessionID || got.TokenID == "" {
+				t.Errorf("ParseJWT() = %+v, want user %v and session %v with a token ID", got, userID, sessionID)
+			}
+		})
+	}
+}
+
 func TestGetBearerToken(t *testing.T) {
 	tests := []struct {
 		name      string
@@ -155,3 +253,18 @@ func TestGetBearerToken(t *testing.T) {
 		})
 	}
 }
+
+type denylist map[string]bool
+
+func (d denylist) Denied(ctx context.Context, tokenID string) (bool, error) {
+	return d[tokenID], nil
+}
+
+func secretKeyring(t *testing.T, secret string) *Keyring {
+	t.Helper()
+	keys, err := NewKeyring(NewSecretKey("", secret))
+	if err != nil {
+		t.Fatalf("NewKeyring() error = %v", err)
+	}
+	return keys
+}
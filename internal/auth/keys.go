@@ -0,0 +1,275 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Algorithm is the JWT "alg" a key signs with
+type Algorithm string
+
+const (
+	AlgorithmEdDSA Algorithm = "EdDSA"
+	AlgorithmRS256 Algorithm = "RS256"
+	AlgorithmHS256 Algorithm = "HS256"
+)
+
+// Smallest RSA key we'll sign with
+const minRSABits = 2048
+
+var validKeyID = regexp.MustCompile(`^[A-Za-z0-9._-]{1,64}$`)
+
+// ErrUnknownKey means a JWT names a key the keyring doesn't have
+var ErrUnknownKey = errors.New("unknown signing key")
+
+// Key signs and verifies JWTs. Its ID goes in the "kid" header of tokens it
+// signs, except for a secret with no ID, which makes tokens without one.
+type Key struct {
+	ID        string
+	Algorithm Algorithm
+	// AddedAt is when the key was published, used to pick which one signs
+	AddedAt time.Time
+
+	private crypto.PrivateKey
+	public  crypto.PublicKey
+}
+
+// NewSecretKey makes an HS256 key from a shared secret. It verifies like
+// any other key but never shows up in the JWKS.
+func NewSecretKey(id, secret string) *Key {
+	return &Key{
+		ID:        id,
+		Algorithm: AlgorithmHS256,
+		private:   []byte(secret),
+		public:    []byte(secret),
+	}
+}
+
+// ParsePrivateKey reads a PKCS #8 PEM private key. Ed25519 keys sign with
+// EdDSA and RSA keys with RS256.
+func ParsePrivateKey(id string, data []byte) (*Key, error) {
+	if !validKeyID.MatchString(id) {
+		return nil, fmt.Errorf("invalid key ID %q", id)
+	}
+	block, _ := pem.Decode(data)
+	if block == nil || block.Type != "PRIVATE KEY" {
+		return nil, errors.New("expected a PKCS #8 \"PRIVATE KEY\" PEM block")
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+
+	switch private := parsed.(type) {
+	case ed25519.PrivateKey:
+		return &Key{ID: id, Algorithm: AlgorithmEdDSA, private: private, public: private.Public()}, nil
+	case *rsa.PrivateKey:
+		if private.N.BitLen() < minRSABits {
+			return nil, fmt.Errorf("RSA keys must be at least %d bits", minRSABits)
+		}
+		return &Key{ID: id, Algorithm: AlgorithmRS256, private: private, public: private.Public()}, nil
+	}
+	return nil, fmt.Errorf("unsupported key type %T", parsed)
+}
+
+// LoadKeyDir reads every <kid>.pem file in dir. Each key's AddedAt is when
+// its file was last modified.
+func LoadKeyDir(dir string) ([]*Key, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.pem"))
+	if err != nil {
+		return nil, err
+	}
+	keys := []*Key{}
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, err
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		key, err := ParsePrivateKey(strings.TrimSuffix(filepath.Base(path), ".pem"), data)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		key.AddedAt = info.ModTime()
+		keys = append(keys, key)
+	}
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("no .pem keys in %s", dir)
+	}
+	return keys, nil
+}
+
+// ChooseSigningKey picks the newest key that has been published for at
+// least publishDelay, so services caching the JWKS have seen it before any
+// token uses it. When every key is newer than that, the oldest one signs.
+func ChooseSigningKey(keys []*Key, publishDelay time.Duration, now time.Time) *Key {
+	if len(keys) == 0 {
+		return nil
+	}
+	sorted := append([]*Key{}, keys...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].AddedAt.Equal(sorted[j].AddedAt) {
+			return sorted[i].ID < sorted[j].ID
+		}
+		return sorted[i].AddedAt.Before(sorted[j].AddedAt)
+	})
+	for i := len(sorted) - 1; i >= 0; i-- {
+		if !sorted[i].AddedAt.After(now.Add(-publishDelay)) {
+			return sorted[i]
+		}
+	}
+	return sorted[0]
+}
+
+func (k *Key) method() jwt.SigningMethod {
+	switch k.Algorithm {
+	case AlgorithmEdDSA:
+		return jwt.SigningMethodEdDSA
+	case AlgorithmRS256:
+		return jwt.SigningMethodRS256
+	}
+	return jwt.SigningMethodHS256
+}
+
+// Keyring holds the key that signs new tokens and every key that still
+// verifies them. The keys can be replaced while it's in use.
+type Keyring struct {
+	state atomic.Pointer[keyringState]
+}
+
+type keyringState struct {
+	signing *Key
+	keys    map[string]*Key
+}
+
+// NewKeyring makes a keyring signing with signing. The others only verify.
+func NewKeyring(signing *Key, others ...*Key) (*Keyring, error) {
+	k := &Keyring{}
+	err := k.Replace(signing, others...)
+	if err != nil {
+		return nil, err
+	}
+	return k, nil
+}
+
+// Replace swaps in a new set of keys. Tokens signed by a key that's left out
+// stop verifying.
+func (k *Keyring) Replace(signing *Key, others ...*Key) error {
+	if signing == nil {
+		return errors.New("no signing key")
+	}
+	state := &keyringState{signing: signing, keys: map[string]*Key{}}
+	for _, key := range append([]*Key{signing}, others...) {
+		if existing, ok := state.keys[key.ID]; ok && existing != key {
+			return fmt.Errorf("duplicate key ID %q", key.ID)
+		}
+		state.keys[key.ID] = key
+	}
+	k.state.Store(state)
+	return nil
+}
+
+// SigningKey returns the key new tokens are signed with
+func (k *Keyring) SigningKey() *Key {
+	return k.state.Load().signing
+}
+
+// Sign signs claims with the signing key
+func (k *Keyring) Sign(claims jwt.Claims) (string, error) {
+	key := k.SigningKey()
+	token := jwt.NewWithClaims(key.method(), claims)
+	if key.ID != "" {
+		token.Header["kid"] = key.ID
+	}
+	return token.SignedString(key.private)
+}
+
+// algorithms lists the algorithms the keyring's keys sign with. Tokens using
+// any other are turned down before a key is even looked up.
+func (k *Keyring) algorithms() []string {
+	seen := map[Algorithm]bool{}
+	algs := []string{}
+	for _, key := range k.state.Load().keys {
+		if !seen[key.Algorithm] {
+			seen[key.Algorithm] = true
+			algs = append(algs, string(key.Algorithm))
+		}
+	}
+	sort.Strings(algs)
+	return algs
+}
+
+// verificationKey is a jwt.Keyfunc choosing the key by the token's kid. The
+// token has to use the algorithm that key signs with, so a public key can't
+// be passed off as an HMAC secret.
+func (k *Keyring) verificationKey(token *jwt.Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+	key, ok := k.state.Load().keys[kid]
+	if !ok {
+		return nil, ErrUnknownKey
+	}
+	if token.Method.Alg() != string(key.Algorithm) {
+		return nil, fmt.Errorf("key %q doesn't sign with %s", kid, token.Method.Alg())
+	}
+	return key.public, nil
+}
+
+// JWK is a public key in JSON Web Key form
+type JWK struct {
+	KeyType   string `json:"kty"`
+	KeyID     string `json:"kid"`
+	Algorithm string `json:"alg"`
+	Use       string `json:"use"`
+	Curve     string `json:"crv,omitempty"`
+	X         string `json:"x,omitempty"`
+	N         string `json:"n,omitempty"`
+	E         string `json:"e,omitempty"`
+}
+
+// JWKS is the public half of every asymmetric key in the keyring, for
+// other services to verify tokens with
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+// JWKS returns the keyring's public keys, sorted by ID
+func (k *Keyring) JWKS() JWKS {
+	set := JWKS{Keys: []JWK{}}
+	for _, key := range k.state.Load().keys {
+		jwk := JWK{KeyID: key.ID, Algorithm: string(key.Algorithm), Use: "sig"}
+		switch public := key.public.(type) {
+		case ed25519.PublicKey:
+			jwk.KeyType = "OKP"
+			jwk.Curve = "Ed25519"
+			jwk.X = base64.RawURLEncoding.EncodeToString(public)
+		case *rsa.PublicKey:
+			jwk.KeyType = "RSA"
+			jwk.N = base64.RawURLEncoding.EncodeToString(public.N.Bytes())
+			jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(public.E)).Bytes())
+		default:
+			continue
+		}
+		set.Keys = append(set.Keys, jwk)
+	}
+	sort.Slice(set.Keys, func(i, j int) bool { return set.Keys[i].KeyID < set.Keys[j].KeyID })
+	return set
+}
@@ -0,0 +1,231 @@
+package auth
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+func TestKeyringRotation(t *testing.T) {
+	oldKey := newEd25519Key(t, "old")
+	newKey := newRSAKey(t, "new", 2048)
+	userID := uuid.New()
+
+	keys, err := NewKeyring(oldKey)
+	if err != nil {
+		t.Fatalf("NewKeyring() error = %v", err)
+	}
+	oldToken, err := MakeJWT(userID, uuid.New(), JWTConfig{Keys: keys}, time.Hour)
+	if err != nil {
+		t.Fatalf("MakeJWT() error = %v", err)
+	}
+
+	// The new key signs, the old one still verifies what it signed
+	err = keys.Replace(newKey, oldKey)
+	if err != nil {
+		t.Fatalf("Replace() error = %v", err)
+	}
+	newToken, err := MakeJWT(userID, uuid.New(), JWTConfig{Keys: keys}, time.Hour)
+	if err != nil {
+		t.Fatalf("MakeJWT() error = %v", err)
+	}
+	for name, token := range map[string]string{"old": oldToken, "new": newToken} {
+		got, err := ValidateJWT(context.Background(), token, JWTConfig{Keys: keys})
+		if err != nil || got != userID {
+			t.Errorf("ValidateJWT(%s token) = %v, %v, want %v", name, got, err, userID)
+		}
+	}
+	parsed, _, err := jwt.NewParser().ParseUnverified(newToken, jwt.MapClaims{})
+	if err != nil {
+		t.Fatalf("ParseUnverified() error = %v", err)
+	}
+	if parsed.Header["kid"] != "new" || parsed.Header["alg"] != "RS256" {
+		t.Errorf("header = %v, want kid new and alg RS256", parsed.Header)
+	}
+
+	// Retiring the old key stops its tokens verifying
+	err = keys.Replace(newKey)
+	if err != nil {
+		t.Fatalf("Replace() error = %v", err)
+	}
+	_, err = ValidateJWT(context.Background(), oldToken, JWTConfig{Keys: keys})
+	if !errors.Is(err, ErrBadSignature) {
+		t.Errorf("ValidateJWT(old token) error = %v, want ErrBadSignature", err)
+	}
+}
+
+func TestKeyringRejectsAlgorithmSwitch(t *testing.T) {
+	key := newEd25519Key(t, "ed")
+	keys, err := NewKeyring(key)
+	if err != nil {
+		t.Fatalf("NewKeyring() error = %v", err)
+	}
+
+	// An HS256 token keyed with the public key, which anyone can get
+	forged := jwt.NewWithClaims(jwt.SigningMethodHS256, accessClaims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    string(TokenTypeAccess),
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+			Subject:   uuid.New().String(),
+		},
+		SessionID: uuid.New().String(),
+	})
+	forged.Header["kid"] = "ed"
+	tokenString, err := forged.SignedString([]byte(key.public.(ed25519.PublicKey)))
+	if err != nil {
+		t.Fatalf("SignedString() error = %v", err)
+	}
+
+	_, err = ValidateJWT(context.Background(), tokenString, JWTConfig{Keys: keys})
+	if !errors.Is(err, ErrBadSignature) {
+		t.Errorf("ValidateJWT() error = %v, want ErrBadSignature", err)
+	}
+}
+
+func TestChooseSigningKey(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	oldest := &Key{ID: "a", AddedAt: now.Add(-48 * time.Hour)}
+	published := &Key{ID: "b", AddedAt: now.Add(-2 * time.Hour)}
+	fresh := &Key{ID: "c", AddedAt: now.Add(-10 * time.Minute)}
+
+	tests := []struct {
+		name string
+		keys []*Key
+		want *Key
+	}{
+		{name: "One key", keys: []*Key{fresh}, want: fresh},
+		{name: "Newest published", keys: []*Key{fresh, oldest, published}, want: published},
+		{name: "Only fresh keys", keys: []*Key{fresh, {ID: "d", AddedAt: now}}, want: fresh},
+		{name: "No keys", keys: nil, want: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ChooseSigningKey(tt.keys, time.Hour, now); got != tt.want {
+				t.Errorf("ChooseSigningKey() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestJWKS(t *testing.T) {
+	edKey := newEd25519Key(t, "ed")
+	rsaKey := newRSAKey(t, "rsa", 2048)
+	keys, err := NewKeyring(edKey, rsaKey, NewSecretKey("", "secret"))
+	if err != nil {
+		t.Fatalf("NewKeyring() error = %v", err)
+	}
+
+	set := keys.JWKS()
+	if len(set.Keys) != 2 {
+		t.Fatalf("JWKS() has %d keys, want 2 without the secret", len(set.Keys))
+	}
+	if got := set.Keys[0]; got.KeyID != "ed" || got.KeyType != "OKP" || got.Curve != "Ed25519" || got.Algorithm != "EdDSA" || got.X == "" {
+		t.Errorf("Ed25519 JWK = %+v", got)
+	}
+	if got := set.Keys[1]; got.KeyID != "rsa" || got.KeyType != "RSA" || got.Algorithm != "RS256" || got.N == "" || got.E != "AQAB" {
+		t.Errorf("RSA JWK = %+v", got)
+	}
+}
+
+func TestParsePrivateKey(t *testing.T) {
+	_, edPrivate, _ := ed25519.GenerateKey(rand.Reader)
+	smallRSA, _ := rsa.GenerateKey(rand.Reader, 1024)
+
+	tests := []struct {
+		name    string
+		id      string
+		data    []byte
+		wantAlg Algorithm
+		wantErr bool
+	}{
+		{name: "Ed25519", id: "2024-06", data: pkcs8PEM(t, edPrivate), wantAlg: AlgorithmEdDSA},
+		{name: "RSA too small", id: "small", data: pkcs8PEM(t, smallRSA), wantErr: true},
+		{name: "Bad ID", id: "../etc", data: pkcs8PEM(t, edPrivate), wantErr: true},
+		{name: "Not PEM", id: "junk", data: []byte("junk"), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			key, err := ParsePrivateKey(tt.id, tt.data)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParsePrivateKey() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && key.Algorithm != tt.wantAlg {
+				t.Errorf("ParsePrivateKey() algorithm = %v, want %v", key.Algorithm, tt.wantAlg)
+			}
+		})
+	}
+}
+
+func TestLoadKeyDir(t *testing.T) {
+	dir := t.TempDir()
+	_, edPrivate, _ := ed25519.GenerateKey(rand.Reader)
+	err := os.WriteFile(filepath.Join(dir, "2024-06.pem"), pkcs8PEM(t, edPrivate), 0o600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = os.WriteFile(filepath.Join(dir, "README"), []byte("not a key"), 0o600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	keys, err := LoadKeyDir(dir)
+	if err != nil {
+		t.Fatalf("LoadKeyDir() error = %v", err)
+	}
+	if len(keys) != 1 || keys[0].ID != "2024-06" || keys[0].AddedAt.IsZero() {
+		t.Errorf("LoadKeyDir() = %+v, want one key 2024-06 with AddedAt set", keys)
+	}
+
+	_, err = LoadKeyDir(t.TempDir())
+	if err == nil {
+		t.Errorf("LoadKeyDir() of an empty dir succeeded")
+	}
+}
+
+func newEd25519Key(t *testing.T, id string) *Key {
+	t.Helper()
+	_, private, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	key, err := ParsePrivateKey(id, pkcs8PEM(t, private))
+	if err != nil {
+		t.Fatalf("ParsePrivateKey() error = %v", err)
+	}
+	return key
+}
+
+func newRSAKey(t *testing.T, id string, bits int) *Key {
+	t.Helper()
+	private, err := rsa.GenerateKey(rand.Reader, bits)
+	if err != nil {
+		t.Fatal(err)
+	}
+	key, err := ParsePrivateKey(id, pkcs8PEM(t, private))
+	if err != nil {
+		t.Fatalf("ParsePrivateKey() error = %v", err)
+	}
+	return key
+}
+
+func pkcs8PEM(t *testing.T, private interface{}) []byte {
+	t.Helper()
+	der, err := x509.MarshalPKCS8PrivateKey(private)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})
+}
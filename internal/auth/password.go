@@ -0,0 +1,209 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// ErrPasswordMismatch means the password isn't the one the hash was made from
+var ErrPasswordMismatch = errors.New("password doesn't match")
+
+// ErrUnknownHash means a stored hash isn't in a format we can check
+var ErrUnknownHash = errors.New("unrecognized password hash")
+
+// PasswordParams is how much work hashing a password with argon2id takes
+type PasswordParams struct {
+	// Memory is in KiB
+	Memory      uint32
+	Iterations  uint32
+	Parallelism uint8
+	SaltLength  uint32
+	KeyLength   uint32
+}
+
+// DefaultPasswordParams are the second recommended option from RFC 9106
+var DefaultPasswordParams = PasswordParams{
+	Memory:      64 * 1024,
+	Iterations:  3,
+	Parallelism: 4,
+	SaltLength:  16,
+	KeyLength:   32,
+}
+
+// ParsePasswordParams reads params written like the middle of a PHC string,
+// "m=65536,t=3,p=4". Anything left out keeps its default.
+func ParsePasswordParams(s string) (PasswordParams, error) {
+	params := DefaultPasswordParams
+	for _, field := range strings.Split(s, ",") {
+		name, value, ok := strings.Cut(strings.TrimSpace(field), "=")
+		if !ok {
+			return PasswordParams{}, fmt.Errorf("invalid password hash parameter %q", field)
+		}
+		var err error
+		switch name {
+		case "m":
+			params.Memory, err = parseUint32(value)
+		case "t":
+			params.Iterations, err = parseUint32(value)
+		case "p":
+			var p uint64
+			p, err = strconv.ParseUint(value, 10, 8)
+			params.Parallelism = uint8(p)
+		default:
+			err = errors.New("unknown parameter")
+		}
+		if err != nil {
+			return PasswordParams{}, fmt.Errorf("invalid password hash parameter %q: %w", field, err)
+		}
+	}
+	err := params.validate()
+	if err != nil {
+		return PasswordParams{}, err
+	}
+	return params, nil
+}
+
+func parseUint32(s string) (uint32, error) {
+	n, err := strconv.ParseUint(s, 10, 32)
+	return uint32(n), err
+}
+
+// String is the params as they appear in a PHC string
+func (p PasswordParams) String() string {
+	return fmt.Sprintf("m=%d,t=%d,p=%d", p.Memory, p.Iterations, p.Parallelism)
+}
+
+func (p PasswordParams) validate() error {
+	if p.Iterations < 1 || p.Parallelism < 1 {
+		return errors.New("argon2id needs at least one iteration and one thread")
+	}
+	if p.Memory < 8*uint32(p.Parallelism) {
+		return errors.New("argon2id needs at least 8 KiB of memory per thread")
+	}
+	if p.SaltLength < 8 || p.KeyLength < 16 {
+		return errors.New("argon2id salt or key is too short")
+	}
+	return nil
+}
+
+// PasswordHasher hashes passwords with argon2id in PHC string format:
+//
+//	$argon2id$v=19$m=65536,t=3,p=4$<salt>$<key>
+//
+// It checks those and older bcrypt hashes, and says when a hash was made
+// with anything other than its current params so it can be replaced.
+type PasswordHasher struct {
+	params PasswordParams
+}
+
+// NewPasswordHasher makes a hasher using params for new hashes
+func NewPasswordHasher(params PasswordParams) (*PasswordHasher, error) {
+	err := params.validate()
+	if err != nil {
+		return nil, err
+	}
+	return &PasswordHasher{params: params}, nil
+}
+
+// Hash hashes a password with a new random salt
+func (h *PasswordHasher) Hash(password string) (string, error) {
+	salt := make([]byte, h.params.SaltLength)
+	_, err := rand.Read(salt)
+	if err != nil {
+		return "", err
+	}
+	key := argon2.IDKey([]byte(password), salt, h.params.Iterations, h.params.Memory, h.params.Parallelism, h.params.KeyLength)
+	return fmt.Sprintf(
+		"$argon2id$v=%d$%s$%s$%s",
+		argon2.Version,
+		h.params,
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(key),
+	), nil
+}
+
+// Verify checks a password against a stored hash. needsRehash is true when
+// the password matched but the hash should be replaced with a new one from
+// Hash, because it's bcrypt or used different params.
+func (h *PasswordHasher) Verify(password, hash string) (needsRehash bool, err error) {
+	if isBcryptHash(hash) {
+		err := bcrypt.CompareHashAndPassword([]byte(hash), []byte(password))
+		if errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
+			return false, ErrPasswordMismatch
+		}
+		if err != nil {
+			return false, err
+		}
+		return true, nil
+	}
+
+	params, salt, key, err := decodeArgon2idHash(hash)
+	if err != nil {
+		return false, err
+	}
+	other := argon2.IDKey([]byte(password), salt, params.Iterations, params.Memory, params.Parallelism, params.KeyLength)
+	if subtle.ConstantTimeCompare(key, other) != 1 {
+		return false, ErrPasswordMismatch
+	}
+	return params != h.params, nil
+}
+
+func isBcryptHash(hash string) bool {
+	for _, prefix := range []string{"$2a$", "$2b$", "$2y$"} {
+		if strings.HasPrefix(hash, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+func decodeArgon2idHash(hash string) (PasswordParams, []byte, []byte, error) {
+	// "", "argon2id", "v=19", params, salt, key
+	parts := strings.Split(hash, "$")
+	if len(parts) != 6 || parts[0] != "" || parts[1] != "argon2id" {
+		return PasswordParams{}, nil, nil, ErrUnknownHash
+	}
+	if parts[2] != fmt.Sprintf("v=%d", argon2.Version) {
+		return PasswordParams{}, nil, nil, fmt.Errorf("%w: argon2 version %q", ErrUnknownHash, parts[2])
+	}
+	params, err := ParsePasswordParams(parts[3])
+	if err != nil {
+		return PasswordParams{}, nil, nil, fmt.Errorf("%w: %w", ErrUnknownHash, err)
+	}
+	salt, err := base64.RawStdEncoding.DecodeString(parts[4])
+	if err != nil {
+		return PasswordParams{}, nil, nil, fmt.Errorf("%w: salt: %w", ErrUnknownHash, err)
+	}
+	key, err := base64.RawStdEncoding.DecodeString(parts[5])
+	if err != nil {
+		return PasswordParams{}, nil, nil, fmt.Errorf("%w: key: %w", ErrUnknownHash, err)
+	}
+	params.SaltLength = uint32(len(salt))
+	params.KeyLength = uint32(len(key))
+	err = params.validate()
+	if err != nil {
+		return PasswordParams{}, nil, nil, fmt.Errorf("%w: %w", ErrUnknownHash, err)
+	}
+	return params, salt, key, nil
+}
+
+var defaultPasswordHasher = &PasswordHasher{params: DefaultPasswordParams}
+
+// HashPassword hashes a password with the default params
+func HashPassword(password string) (string, error) {
+	return defaultPasswordHasher.Hash(password)
+}
+
+// CheckPasswordHash returns nil if the password matches the hash
+func CheckPasswordHash(password, hash string) error {
+	_, err := defaultPasswordHasher.Verify(password, hash)
+	return err
+}
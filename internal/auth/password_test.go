@@ -0,0 +1,120 @@
+package auth
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+func TestPasswordHasherVerify(t *testing.T) {
+	// Cheap params so the tests run quickly
+	params := PasswordParams{Memory: 1024, Iterations: 1, Parallelism: 1, SaltLength: 16, KeyLength: 32}
+	hasher, err := NewPasswordHasher(params)
+	if err != nil {
+		t.Fatalf("NewPasswordHasher() error = %v", err)
+	}
+	oldParams := params
+	oldParams.Iterations = 2
+	oldHasher, err := NewPasswordHasher(oldParams)
+	if err != nil {
+		t.Fatalf("NewPasswordHasher() error = %v", err)
+	}
+
+	password := "correctPassword123!"
+	current, _ := hasher.Hash(password)
+	outdated, _ := oldHasher.Hash(password)
+	bcryptHash, _ := bcrypt.GenerateFromPassword([]byte(password), bcrypt.MinCost)
+	// bcrypt would only have looked at the first 72 bytes of these
+	long := strings.Repeat("a", 72)
+	longHash, _ := hasher.Hash(long + "1")
+
+	tests := []struct {
+		name            string
+		password        string
+		hash            string
+		wantNeedsRehash bool
+		wantErr         error
+	}{
+		{name: "Current", password: password, hash: current},
+		{name: "Wrong password", password: "wrong", hash: current, wantErr: ErrPasswordMismatch},
+		{name: "Outdated params", password: password, hash: outdated, wantNeedsRehash: true},
+		{name: "Bcrypt", password: password, hash: string(bcryptHash), wantNeedsRehash: true},
+		{name: "Bcrypt wrong password", password: "wrong", hash: string(bcryptHash), wantErr: ErrPasswordMismatch},
+		{name: "Long password", password: long + "1", hash: longHash},
+		{name: "Long password differs after 72 bytes", password: long + "2", hash: longHash, wantErr: ErrPasswordMismatch},
+		{name: "Unknown version", password: password, hash: strings.Replace(current, "v=19", "v=16", 1), wantErr: ErrUnknownHash},
+		{name: "Garbage", password: password, hash: "not a hash", wantErr: ErrUnknownHash},
+		{name: "Empty", password: password, hash: "", wantErr: ErrUnknownHash},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			needsRehash, err := hasher.Verify(tt.password, tt.hash)
+			if !errors.Is(err, tt.wantErr) || (err != nil) != (tt.wantErr != nil) {
+				t.Fatalf("Verify() error = %v, want %v", err, tt.wantErr)
+			}
+			if needsRehash != tt.wantNeedsRehash {
+				t.Errorf("Verify() needsRehash = %v, want %v", needsRehash, tt.wantNeedsRehash)
+			}
+		})
+	}
+}
+
+func TestPasswordHasherHash(t *testing.T) {
+	hasher, err := NewPasswordHasher(PasswordParams{Memory: 1024, Iterations: 1, Parallelism: 1, SaltLength: 16, KeyLength: 32})
+	if err != nil {
+		t.Fatalf("NewPasswordHasher() error = %v", err)
+	}
+	hash1, err := hasher.Hash("password")
+	if err != nil {
+		t.Fatalf("Hash() error = %v", err)
+	}
+	hash2, _ := hasher.Hash("password")
+
+	if !strings.HasPrefix(hash1, "$argon2id$v=19$m=1024,t=1,p=1$") {
+		t.Errorf("Hash() = %q, want a PHC argon2id string", hash1)
+	}
+	if hash1 == hash2 {
+		t.Errorf("Hash() gave the same hash twice, want a new salt each time")
+	}
+}
+
+func TestParsePasswordParams(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    PasswordParams
+		wantErr bool
+	}{
+		{
+			name:  "All set",
+			input: "m=19456,t=2,p=1",
+			want:  PasswordParams{Memory: 19456, Iterations: 2, Parallelism: 1, SaltLength: 16, KeyLength: 32},
+		},
+		{
+			name:  "Defaults for the rest",
+			input: "t=5",
+			want:  PasswordParams{Memory: 64 * 1024, Iterations: 5, Parallelism: 4, SaltLength: 16, KeyLength: 32},
+		},
+		{name: "Unknown parameter", input: "m=19456,x=1", wantErr: true},
+		{name: "Not a number", input: "t=lots", wantErr: true},
+		{name: "Too many threads", input: "p=300", wantErr: true},
+		{name: "No iterations", input: "t=0", wantErr: true},
+		{name: "Too little memory", input: "m=16,p=4", wantErr: true},
+		{name: "Missing value", input: "m", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParsePasswordParams(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParsePasswordParams(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParsePasswordParams(%q) = %+v, want %+v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,95 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"os"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+// testQueries runs queries in a transaction against the migrated database in
+// TEST_DB_URL, rolled back when the test ends. Without it the test is
+// skipped.
+func testQueries(t *testing.T) *Queries {
+	t.Helper()
+	dbURL := os.Getenv("TEST_DB_URL")
+	if dbURL == "" {
+		t.Skip("TEST_DB_URL isn't set")
+	}
+	db, err := sql.Open("postgres", dbURL)
+	if err != nil {
+		t.Fatalf("sql.Open() error = %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	tx, err := db.Begin()
+	if err != nil {
+		t.Fatalf("Begin() error = %v", err)
+	}
+	t.Cleanup(func() { tx.Rollback() })
+	return New(tx)
+}
+
+func TestIsBlockedInConversation(t *testing.T) {
+	ctx := context.Background()
+	q := testQueries(t)
+
+	newUser := func() uuid.UUID {
+		t.Helper()
+		user, err := q.CreateUser(ctx, CreateUserParams{Email: uuid.NewString() + "@example.com"})
+		if err != nil {
+			t.Fatalf("CreateUser() error = %v", err)
+		}
+		return user.ID
+	}
+	blocker, blocked, bystander := newUser(), newUser(), newUser()
+	err := q.CreateBlock(ctx, CreateBlockParams{BlockerID: blocker, BlockedID: blocked})
+	if err != nil {
+		t.Fatalf("CreateBlock() error = %v", err)
+	}
+	newConversation := func(userIDs ...uuid.UUID) uuid.UUID {
+		t.Helper()
+		conversation, err := q.CreateConversation(ctx, sql.NullString{})
+		if err != nil {
+			t.Fatalf("CreateConversation() error = %v", err)
+		}
+		err = q.AddConversationParticipants(ctx, AddConversationParticipantsParams{
+			ConversationID: conversation.ID,
+			UserIds:        userIDs,
+		})
+		if err != nil {
+			t.Fatalf("AddConversationParticipants() error = %v", err)
+		}
+		return conversation.ID
+	}
+	withBoth := newConversation(blocker, blocked, bystander)
+	withoutBlocked := newConversation(blocker, bystander)
+
+	tests := []struct {
+		name           string
+		conversationID uuid.UUID
+		userID         uuid.UUID
+		want           bool
+	}{
+		{name: "Blocked user sends", conversationID: withBoth, userID: blocked, want: true},
+		{name: "Blocker sends", conversationID: withBoth, userID: blocker, want: true},
+		{name: "Bystander sends", conversationID: withBoth, userID: bystander, want: false},
+		{name: "Blocker sends without the blocked user", conversationID: withoutBlocked, userID: blocker, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := q.IsBlockedInConversation(ctx, IsBlockedInConversationParams{
+				UserID:         tt.userID,
+				ConversationID: tt.conversationID,
+			})
+			if err != nil {
+				t.Fatalf("IsBlockedInConversation() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("IsBlockedInConversation() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
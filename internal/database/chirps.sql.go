@@ -7,29 +7,49 @@ package database
 
 import (
 	"context"
+	"database/sql"
 
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 )
 
 const createChirp = `-- name: CreateChirp :one
-INSERT INTO chirps (id, created_at, updated_at, body, user_id)
-VALUES (
-    gen_random_uuid(),
+WITH new_chirp AS (
+    SELECT gen_random_uuid() AS id
+), parent AS (
+    SELECT chirps.id, chirps.thread_id, chirps.ancestor_ids FROM chirps
+    WHERE chirps.id = $1
+)
+INSERT INTO chirps (id, created_at, updated_at, body, user_id, in_reply_to, thread_id, ancestor_ids, rechirp_of)
+SELECT
+    new_chirp.id,
     NOW(),
     NOW(),
-    $1,
-    $2
-)
-RETURNING id, created_at, updated_at, body, user_id
+    $2,
+    $3,
+    parent.id,
+    COALESCE(parent.thread_id, new_chirp.id),
+    COALESCE(parent.ancestor_ids || parent.id, '{}'),
+    $4
+FROM new_chirp
+LEFT JOIN parent ON TRUE
+RETURNING id, created_at, updated_at, body, user_id, in_reply_to, thread_id, ancestor_ids, like_count, rechirp_of, version, search_vector, hidden_at
 `
 
 type CreateChirpParams struct {
-	Body   string
-	UserID uuid.UUID
+	InReplyTo uuid.NullUUID
+	Body      string
+	UserID    uuid.UUID
+	RechirpOf uuid.NullUUID
 }
 
 func (q *Queries) CreateChirp(ctx context.Context, arg CreateChirpParams) (Chirp, error) {
-	row := q.db.QueryRowContext(ctx, createChirp, arg.Body, arg.UserID)
+	row := q.db.QueryRowContext(ctx, createChirp,
+		arg.InReplyTo,
+		arg.Body,
+		arg.UserID,
+		arg.RechirpOf,
+	)
 	var i Chirp
 	err := row.Scan(
 		&i.ID,
@@ -37,6 +57,14 @@ func (q *Queries) CreateChirp(ctx context.Context, arg CreateChirpParams) (Chirp
 		&i.UpdatedAt,
 		&i.Body,
 		&i.UserID,
+		&i.InReplyTo,
+		&i.ThreadID,
+		pq.Array(&i.AncestorIds),
+		&i.LikeCount,
+		&i.RechirpOf,
+		&i.Version,
+		&i.SearchVector,
+		&i.HiddenAt,
 	)
 	return i, err
 }
@@ -52,7 +80,7 @@ func (q *Queries) DeleteChirp(ctx context.Context, id uuid.UUID) error {
 }
 
 const getChirp = `-- name: GetChirp :one
-SELECT id, created_at, updated_at, body, user_id FROM chirps
+SELECT id, created_at, updated_at, body, user_id, in_reply_to, thread_id, ancestor_ids, like_count, rechirp_of, version, search_vector, hidden_at FROM chirps
 WHERE id = $1
 `
 
@@ -65,17 +93,417 @@ func (q *Queries) GetChirp(ctx context.Context, id uuid.UUID) (Chirp, error) {
 		&i.UpdatedAt,
 		&i.Body,
 		&i.UserID,
+		&i.InReplyTo,
+		&i.ThreadID,
+		pq.Array(&i.AncestorIds),
+		&i.LikeCount,
+		&i.RechirpOf,
+		&i.Version,
+		&i.SearchVector,
+		&i.HiddenAt,
+	)
+	return i, err
+}
+
+const getChirpsByIDs = `-- name: GetChirpsByIDs :many
+SELECT id, created_at, updated_at, body, user_id, in_reply_to, thread_id, ancestor_ids, like_count, rechirp_of, version, search_vector, hidden_at FROM chirps
+WHERE id = ANY($1::uuid[])
+`
+
+func (q *Queries) GetChirpsByIDs(ctx context.Context, ids []uuid.UUID) ([]Chirp, error) {
+	rows, err := q.db.QueryContext(ctx, getChirpsByIDs, pq.Array(ids))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Chirp
+	for rows.Next() {
+		var i Chirp
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Body,
+			&i.UserID,
+			&i.InReplyTo,
+			&i.ThreadID,
+			pq.Array(&i.AncestorIds),
+			&i.LikeCount,
+			&i.RechirpOf,
+			&i.Version,
+			&i.SearchVector,
+			&i.HiddenAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getPlainRechirp = `-- name: GetPlainRechirp :one
+SELECT id, created_at, updated_at, body, user_id, in_reply_to, thread_id, ancestor_ids, like_count, rechirp_of, version, search_vector, hidden_at FROM chirps
+WHERE user_id = $1 AND rechirp_of = $2 AND body = ''
+`
+
+type GetPlainRechirpParams struct {
+	UserID    uuid.UUID
+	RechirpOf uuid.NullUUID
+}
+
+func (q *Queries) GetPlainRechirp(ctx context.Context, arg GetPlainRechirpParams) (Chirp, error) {
+	row := q.db.QueryRowContext(ctx, getPlainRechirp, arg.UserID, arg.RechirpOf)
+	var i Chirp
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Body,
+		&i.UserID,
+		&i.InReplyTo,
+		&i.ThreadID,
+		pq.Array(&i.AncestorIds),
+		&i.LikeCount,
+		&i.RechirpOf,
+		&i.Version,
+		&i.SearchVector,
+		&i.HiddenAt,
+	)
+	return i, err
+}
+
+const hideChirp = `-- name: HideChirp :one
+UPDATE chirps SET hidden_at = NOW()
+WHERE id = $1
+RETURNING id, created_at, updated_at, body, user_id, in_reply_to, thread_id, ancestor_ids, like_count, rechirp_of, version, search_vector, hidden_at
+`
+
+func (q *Queries) HideChirp(ctx context.Context, id uuid.UUID) (Chirp, error) {
+	row := q.db.QueryRowContext(ctx, hideChirp, id)
+	var i Chirp
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Body,
+		&i.UserID,
+		&i.InReplyTo,
+		&i.ThreadID,
+		pq.Array(&i.AncestorIds),
+		&i.LikeCount,
+		&i.RechirpOf,
+		&i.Version,
+		&i.SearchVector,
+		&i.HiddenAt,
 	)
 	return i, err
 }
 
-const getChirpsAsc = `-- name: GetChirpsAsc :many
-SELECT id, created_at, updated_at, body, user_id FROM chirps
-ORDER BY created_at ASC
+const listChirpRepliesAsc = `-- name: ListChirpRepliesAsc :many
+SELECT id, created_at, updated_at, body, user_id, in_reply_to, thread_id, ancestor_ids, like_count, rechirp_of, version, search_vector, hidden_at FROM chirps
+WHERE ancestor_ids @> ARRAY[$1::uuid]
+AND (
+    $2::timestamp IS NULL
+    OR (created_at, id) > ($2::timestamp, $3::uuid)
+)
+AND NOT EXISTS (
+    SELECT 1 FROM hidden_users
+    WHERE hidden_users.user_id = $4 AND hidden_users.hidden_user_id = chirps.user_id
+)
+AND hidden_at IS NULL
+ORDER BY created_at ASC, id ASC
+LIMIT $5
+`
+
+type ListChirpRepliesAscParams struct {
+	ChirpID         uuid.UUID
+	CursorCreatedAt sql.NullTime
+	CursorID        uuid.NullUUID
+	ViewerID        uuid.NullUUID
+	RowLimit        int32
+}
+
+func (q *Queries) ListChirpRepliesAsc(ctx context.Context, arg ListChirpRepliesAscParams) ([]Chirp, error) {
+	rows, err := q.db.QueryContext(ctx, listChirpRepliesAsc,
+		arg.ChirpID,
+		arg.CursorCreatedAt,
+		arg.CursorID,
+		arg.ViewerID,
+		arg.RowLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Chirp
+	for rows.Next() {
+		var i Chirp
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Body,
+			&i.UserID,
+			&i.InReplyTo,
+			&i.ThreadID,
+			pq.Array(&i.AncestorIds),
+			&i.LikeCount,
+			&i.RechirpOf,
+			&i.Version,
+			&i.SearchVector,
+			&i.HiddenAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listChirpRepliesDesc = `-- name: ListChirpRepliesDesc :many
+SELECT id, created_at, updated_at, body, user_id, in_reply_to, thread_id, ancestor_ids, like_count, rechirp_of, version, search_vector, hidden_at FROM chirps
+WHERE ancestor_ids @> ARRAY[$1::uuid]
+AND (
+    $2::timestamp IS NULL
+    OR (created_at, id) < ($2::timestamp, $3::uuid)
+)
+AND NOT EXISTS (
+    SELECT 1 FROM hidden_users
+    WHERE hidden_users.user_id = $4 AND hidden_users.hidden_user_id = chirps.user_id
+)
+AND hidden_at IS NULL
+ORDER BY created_at DESC, id DESC
+LIMIT $5
+`
+
+type ListChirpRepliesDescParams struct {
+	ChirpID         uuid.UUID
+	CursorCreatedAt sql.NullTime
+	CursorID        uuid.NullUUID
+	ViewerID        uuid.NullUUID
+	RowLimit        int32
+}
+
+func (q *Queries) ListChirpRepliesDesc(ctx context.Context, arg ListChirpRepliesDescParams) ([]Chirp, error) {
+	rows, err := q.db.QueryContext(ctx, listChirpRepliesDesc,
+		arg.ChirpID,
+		arg.CursorCreatedAt,
+		arg.CursorID,
+		arg.ViewerID,
+		arg.RowLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Chirp
+	for rows.Next() {
+		var i Chirp
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Body,
+			&i.UserID,
+			&i.InReplyTo,
+			&i.ThreadID,
+			pq.Array(&i.AncestorIds),
+			&i.LikeCount,
+			&i.RechirpOf,
+			&i.Version,
+			&i.SearchVector,
+			&i.HiddenAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listChirpsAsc = `-- name: ListChirpsAsc :many
+SELECT id, created_at, updated_at, body, user_id, in_reply_to, thread_id, ancestor_ids, like_count, rechirp_of, version, search_vector, hidden_at FROM chirps
+WHERE ($1::uuid IS NULL OR user_id = $1)
+AND (
+    $2::timestamp IS NULL
+    OR (created_at, id) > ($2::timestamp, $3::uuid)
+)
+AND NOT EXISTS (
+    SELECT 1 FROM hidden_users
+    WHERE hidden_users.user_id = $4 AND hidden_users.hidden_user_id = chirps.user_id
+)
+AND hidden_at IS NULL
+ORDER BY created_at ASC, id ASC
+LIMIT $5
+`
+
+type ListChirpsAscParams struct {
+	AuthorID        uuid.NullUUID
+	CursorCreatedAt sql.NullTime
+	CursorID        uuid.NullUUID
+	ViewerID        uuid.NullUUID
+	RowLimit        int32
+}
+
+func (q *Queries) ListChirpsAsc(ctx context.Context, arg ListChirpsAscParams) ([]Chirp, error) {
+	rows, err := q.db.QueryContext(ctx, listChirpsAsc,
+		arg.AuthorID,
+		arg.CursorCreatedAt,
+		arg.CursorID,
+		arg.ViewerID,
+		arg.RowLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Chirp
+	for rows.Next() {
+		var i Chirp
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Body,
+			&i.UserID,
+			&i.InReplyTo,
+			&i.ThreadID,
+			pq.Array(&i.AncestorIds),
+			&i.LikeCount,
+			&i.RechirpOf,
+			&i.Version,
+			&i.SearchVector,
+			&i.HiddenAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listChirpsDesc = `-- name: ListChirpsDesc :many
+SELECT id, created_at, updated_at, body, user_id, in_reply_to, thread_id, ancestor_ids, like_count, rechirp_of, version, search_vector, hidden_at FROM chirps
+WHERE ($1::uuid IS NULL OR user_id = $1)
+AND (
+    $2::timestamp IS NULL
+    OR (created_at, id) < ($2::timestamp, $3::uuid)
+)
+AND NOT EXISTS (
+    SELECT 1 FROM hidden_users
+    WHERE hidden_users.user_id = $4 AND hidden_users.hidden_user_id = chirps.user_id
+)
+AND hidden_at IS NULL
+ORDER BY created_at DESC, id DESC
+LIMIT $5
+`
+
+type ListChirpsDescParams struct {
+	AuthorID        uuid.NullUUID
+	CursorCreatedAt sql.NullTime
+	CursorID        uuid.NullUUID
+	ViewerID        uuid.NullUUID
+	RowLimit        int32
+}
+
+func (q *Queries) ListChirpsDesc(ctx context.Context, arg ListChirpsDescParams) ([]Chirp, error) {
+	rows, err := q.db.QueryContext(ctx, listChirpsDesc,
+		arg.AuthorID,
+		arg.CursorCreatedAt,
+		arg.CursorID,
+		arg.ViewerID,
+		arg.RowLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Chirp
+	for rows.Next() {
+		var i Chirp
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Body,
+			&i.UserID,
+			&i.InReplyTo,
+			&i.ThreadID,
+			pq.Array(&i.AncestorIds),
+			&i.LikeCount,
+			&i.RechirpOf,
+			&i.Version,
+			&i.SearchVector,
+			&i.HiddenAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listTimelineAsc = `-- name: ListTimelineAsc :many
+SELECT chirps.id, chirps.created_at, chirps.updated_at, chirps.body, chirps.user_id, chirps.in_reply_to, chirps.thread_id, chirps.ancestor_ids, chirps.like_count, chirps.rechirp_of, chirps.version, chirps.search_vector, chirps.hidden_at FROM chirps
+JOIN follows ON chirps.user_id = follows.followee_id
+WHERE follows.follower_id = $1
+AND (
+    $2::timestamp IS NULL
+    OR (chirps.created_at, chirps.id) > ($2::timestamp, $3::uuid)
+)
+AND NOT EXISTS (
+    SELECT 1 FROM hidden_users
+    WHERE hidden_users.user_id = $1 AND hidden_users.hidden_user_id = chirps.user_id
+)
+AND chirps.hidden_at IS NULL
+ORDER BY chirps.created_at ASC, chirps.id ASC
+LIMIT $4
 `
 
-func (q *Queries) GetChirpsAsc(ctx context.Context) ([]Chirp, error) {
-	rows, err := q.db.QueryContext(ctx, getChirpsAsc)
+type ListTimelineAscParams struct {
+	UserID          uuid.UUID
+	CursorCreatedAt sql.NullTime
+	CursorID        uuid.NullUUID
+	RowLimit        int32
+}
+
+func (q *Queries) ListTimelineAsc(ctx context.Context, arg ListTimelineAscParams) ([]Chirp, error) {
+	rows, err := q.db.QueryContext(ctx, listTimelineAsc,
+		arg.UserID,
+		arg.CursorCreatedAt,
+		arg.CursorID,
+		arg.RowLimit,
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -89,6 +517,14 @@ func (q *Queries) GetChirpsAsc(ctx context.Context) ([]Chirp, error) {
 			&i.UpdatedAt,
 			&i.Body,
 			&i.UserID,
+			&i.InReplyTo,
+			&i.ThreadID,
+			pq.Array(&i.AncestorIds),
+			&i.LikeCount,
+			&i.RechirpOf,
+			&i.Version,
+			&i.SearchVector,
+			&i.HiddenAt,
 		); err != nil {
 			return nil, err
 		}
@@ -103,13 +539,37 @@ func (q *Queries) GetChirpsAsc(ctx context.Context) ([]Chirp, error) {
 	return items, nil
 }
 
-const getChirpsDesc = `-- name: GetChirpsDesc :many
-SELECT id, created_at, updated_at, body, user_id FROM chirps
-ORDER BY created_at DESC
+const listTimelineDesc = `-- name: ListTimelineDesc :many
+SELECT chirps.id, chirps.created_at, chirps.updated_at, chirps.body, chirps.user_id, chirps.in_reply_to, chirps.thread_id, chirps.ancestor_ids, chirps.like_count, chirps.rechirp_of, chirps.version, chirps.search_vector, chirps.hidden_at FROM chirps
+JOIN follows ON chirps.user_id = follows.followee_id
+WHERE follows.follower_id = $1
+AND (
+    $2::timestamp IS NULL
+    OR (chirps.created_at, chirps.id) < ($2::timestamp, $3::uuid)
+)
+AND NOT EXISTS (
+    SELECT 1 FROM hidden_users
+    WHERE hidden_users.user_id = $1 AND hidden_users.hidden_user_id = chirps.user_id
+)
+AND chirps.hidden_at IS NULL
+ORDER BY chirps.created_at DESC, chirps.id DESC
+LIMIT $4
 `
 
-func (q *Queries) GetChirpsDesc(ctx context.Context) ([]Chirp, error) {
-	rows, err := q.db.QueryContext(ctx, getChirpsDesc)
+type ListTimelineDescParams struct {
+	UserID          uuid.UUID
+	CursorCreatedAt sql.NullTime
+	CursorID        uuid.NullUUID
+	RowLimit        int32
+}
+
+func (q *Queries) ListTimelineDesc(ctx context.Context, arg ListTimelineDescParams) ([]Chirp, error) {
+	rows, err := q.db.QueryContext(ctx, listTimelineDesc,
+		arg.UserID,
+		arg.CursorCreatedAt,
+		arg.CursorID,
+		arg.RowLimit,
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -123,6 +583,14 @@ func (q *Queries) GetChirpsDesc(ctx context.Context) ([]Chirp, error) {
 			&i.UpdatedAt,
 			&i.Body,
 			&i.UserID,
+			&i.InReplyTo,
+			&i.ThreadID,
+			pq.Array(&i.AncestorIds),
+			&i.LikeCount,
+			&i.RechirpOf,
+			&i.Version,
+			&i.SearchVector,
+			&i.HiddenAt,
 		); err != nil {
 			return nil, err
 		}
@@ -136,3 +604,40 @@ func (q *Queries) GetChirpsDesc(ctx context.Context) ([]Chirp, error) {
 	}
 	return items, nil
 }
+
+const updateChirpBody = `-- name: UpdateChirpBody :one
+WITH revision AS (
+    INSERT INTO chirp_revisions (chirp_id, version, body, created_at, replaced_at)
+    SELECT chirps.id, chirps.version, chirps.body, chirps.updated_at, NOW() FROM chirps
+    WHERE chirps.id = $1
+)
+UPDATE chirps SET body = $2, version = version + 1, updated_at = NOW()
+WHERE id = $1
+RETURNING id, created_at, updated_at, body, user_id, in_reply_to, thread_id, ancestor_ids, like_count, rechirp_of, version, search_vector, hidden_at
+`
+
+type UpdateChirpBodyParams struct {
+	ID   uuid.UUID
+	Body string
+}
+
+func (q *Queries) UpdateChirpBody(ctx context.Context, arg UpdateChirpBodyParams) (Chirp, error) {
+	row := q.db.QueryRowContext(ctx, updateChirpBody, arg.ID, arg.Body)
+	var i Chirp
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Body,
+		&i.UserID,
+		&i.InReplyTo,
+		&i.ThreadID,
+		pq.Array(&i.AncestorIds),
+		&i.LikeCount,
+		&i.RechirpOf,
+		&i.Version,
+		&i.SearchVector,
+		&i.HiddenAt,
+	)
+	return i, err
+}
@@ -11,12 +11,182 @@ import (
 	"github.com/google/uuid"
 )
 
+type Block struct {
+	BlockerID uuid.UUID
+	BlockedID uuid.UUID
+	CreatedAt time.Time
+}
+
 type Chirp struct {
+	ID           uuid.UUID
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+	Body         string
+	UserID       uuid.UUID
+	InReplyTo    uuid.NullUUID
+	ThreadID     uuid.UUID
+	AncestorIds  []uuid.UUID
+	LikeCount    int32
+	RechirpOf    uuid.NullUUID
+	Version      int32
+	SearchVector interface{}
+	HiddenAt     sql.NullTime
+}
+
+type ChirpEvent struct {
+	ID        int64
+	CreatedAt time.Time
+	Kind      string
+	ChirpID   uuid.UUID
+	UserID    uuid.UUID
+	Tags      []string
+	ThreadID  uuid.UUID
+}
+
+type ChirpHashtag struct {
+	ChirpID   uuid.UUID
+	Tag       string
+	CreatedAt time.Time
+}
+
+type ChirpLike struct {
+	ChirpID   uuid.UUID
+	UserID    uuid.UUID
+	CreatedAt time.Time
+}
+
+type ChirpMention struct {
+	ChirpID    uuid.UUID
+	UserID     uuid.UUID
+	StartIndex int32
+	EndIndex   int32
+}
+
+type ChirpRevision struct {
+	ChirpID    uuid.UUID
+	Version    int32
+	Body       string
+	CreatedAt  time.Time
+	ReplacedAt time.Time
+}
+
+type ContentFilter struct {
+	Name      string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	Action    string
+}
+
+type ContentFilterWord struct {
+	FilterName string
+	Word       string
+}
+
+type Conversation struct {
 	ID        uuid.UUID
 	CreatedAt time.Time
 	UpdatedAt time.Time
-	Body      string
+	DmKey     sql.NullString
+}
+
+type ConversationParticipant struct {
+	ConversationID    uuid.UUID
+	UserID            uuid.UUID
+	JoinedAt          time.Time
+	LastReadMessageID uuid.NullUUID
+	LastReadAt        sql.NullTime
+}
+
+type DeniedToken struct {
+	Jti       string
+	CreatedAt time.Time
+	ExpiresAt time.Time
+}
+
+type Follow struct {
+	FollowerID uuid.UUID
+	FolloweeID uuid.UUID
+	CreatedAt  time.Time
+}
+
+type Hashtag struct {
+	Tag       string
+	CreatedAt time.Time
+}
+
+type Medium struct {
+	ID              uuid.UUID
+	CreatedAt       time.Time
+	UserID          uuid.UUID
+	ChirpID         uuid.NullUUID
+	AttachedAt      sql.NullTime
+	Position        int32
+	ContentType     string
+	StorageKey      string
+	Width           int32
+	Height          int32
+	SizeBytes       int32
+	ThumbnailKey    string
+	ThumbnailWidth  int32
+	ThumbnailHeight int32
+	AltText         string
+}
+
+type Message struct {
+	ID             uuid.UUID
+	CreatedAt      time.Time
+	ConversationID uuid.UUID
+	SenderID       uuid.UUID
+	Body           string
+}
+
+type ModerationDecision struct {
+	ID          uuid.UUID
+	CreatedAt   time.Time
+	ModeratorID uuid.NullUUID
+	ReportID    uuid.UUID
+	TargetKind  string
+	ChirpID     uuid.NullUUID
+	UserID      uuid.UUID
+	Action      string
+	Note        string
+}
+
+type Mute struct {
+	MuterID   uuid.UUID
+	MutedID   uuid.UUID
+	CreatedAt time.Time
+}
+
+type Notification struct {
+	ID        uuid.UUID
+	CreatedAt time.Time
+	UserID    uuid.UUID
+	ActorID   uuid.NullUUID
+	Kind      string
+	ChirpID   uuid.NullUUID
+	ReadAt    sql.NullTime
+}
+
+type Poll struct {
+	ChirpID         uuid.UUID
+	CreatedAt       time.Time
+	ClosesAt        time.Time
+	CloseNotifiedAt sql.NullTime
+}
+
+type PollOption struct {
+	ChirpID  uuid.UUID
+	Position int32
+	Text     string
+}
+
+type PollVote struct {
+	ChirpID   uuid.UUID
 	UserID    uuid.UUID
+	Position  int32
+	CreatedAt time.Time
+	UpdatedAt time.Time
 }
 
 type RefreshToken struct {
@@ -26,6 +196,38 @@ type RefreshToken struct {
 	UserID    uuid.UUID
 	ExpiresAt time.Time
 	RevokedAt sql.NullTime
+	SessionID uuid.UUID
+	UsedAt    sql.NullTime
+}
+
+type Report struct {
+	ID         uuid.UUID
+	CreatedAt  time.Time
+	ReporterID uuid.NullUUID
+	TargetKind string
+	ChirpID    uuid.NullUUID
+	UserID     uuid.UUID
+	Reason     string
+	Details    string
+	DecisionID uuid.NullUUID
+}
+
+type Session struct {
+	ID         uuid.UUID
+	CreatedAt  time.Time
+	UserID     uuid.UUID
+	UserAgent  string
+	IpAddress  string
+	LastUsedAt time.Time
+	ExpiresAt  time.Time
+	RevokedAt  sql.NullTime
+}
+
+type TrendingHashtag struct {
+	Tag        string
+	ComputedAt time.Time
+	Score      float64
+	ChirpCount int32
 }
 
 type User struct {
@@ -35,4 +237,7 @@ type User struct {
 	Email          string
 	HashedPassword string
 	IsChirpyRed    bool
+	Username       sql.NullString
+	DmPolicy       string
+	Role           string
 }
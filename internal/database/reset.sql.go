@@ -11,8 +11,10 @@ import (
 
 const reset = `-- name: Reset :exec
 DELETE FROM users
+WHERE role <> 'admin'
 `
 
+// Admins are kept so someone can still reset the server afterwards
 func (q *Queries) Reset(ctx context.Context) error {
 	_, err := q.db.ExecContext(ctx, reset)
 	return err
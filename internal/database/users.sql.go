@@ -7,29 +7,51 @@ package database
 
 import (
 	"context"
+	"database/sql"
 
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 )
 
+const bootstrapAdmin = `-- name: BootstrapAdmin :execrows
+UPDATE users
+SET role = 'admin', updated_at = NOW()
+WHERE email = $1
+AND NOT EXISTS (
+    SELECT 1 FROM users WHERE role = 'admin'
+)
+`
+
+// Makes the user with this email an admin, but only while there are no admins
+func (q *Queries) BootstrapAdmin(ctx context.Context, email string) (int64, error) {
+	result, err := q.db.ExecContext(ctx, bootstrapAdmin, email)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
 const createUser = `-- name: CreateUser :one
-INSERT INTO users (id, created_at, updated_at, email, hashed_password)
+INSERT INTO users (id, created_at, updated_at, email, hashed_password, username)
 VALUES (
     gen_random_uuid(),
     NOW(),
     NOW(),
     $1,
-    $2
+    $2,
+    $3
 )
-RETURNING id, created_at, updated_at, email, hashed_password, is_chirpy_red
+RETURNING id, created_at, updated_at, email, hashed_password, is_chirpy_red, username, dm_policy, role
 `
 
 type CreateUserParams struct {
 	Email          string
 	HashedPassword string
+	Username       sql.NullString
 }
 
 func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (User, error) {
-	row := q.db.QueryRowContext(ctx, createUser, arg.Email, arg.HashedPassword)
+	row := q.db.QueryRowContext(ctx, createUser, arg.Email, arg.HashedPassword, arg.Username)
 	var i User
 	err := row.Scan(
 		&i.ID,
@@ -38,12 +60,37 @@ func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (User, e
 		&i.Email,
 		&i.HashedPassword,
 		&i.IsChirpyRed,
+		&i.Username,
+		&i.DmPolicy,
+		&i.Role,
+	)
+	return i, err
+}
+
+const getUser = `-- name: GetUser :one
+SELECT id, created_at, updated_at, email, hashed_password, is_chirpy_red, username, dm_policy, role FROM users
+WHERE id = $1
+`
+
+func (q *Queries) GetUser(ctx context.Context, id uuid.UUID) (User, error) {
+	row := q.db.QueryRowContext(ctx, getUser, id)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Email,
+		&i.HashedPassword,
+		&i.IsChirpyRed,
+		&i.Username,
+		&i.DmPolicy,
+		&i.Role,
 	)
 	return i, err
 }
 
 const getUserByEmail = `-- name: GetUserByEmail :one
-SELECT id, created_at, updated_at, email, hashed_password, is_chirpy_red FROM users
+SELECT id, created_at, updated_at, email, hashed_password, is_chirpy_red, username, dm_policy, role FROM users
 WHERE email = $1
 `
 
@@ -57,24 +104,204 @@ func (q *Queries) GetUserByEmail(ctx context.Context, email string) (User, error
 		&i.Email,
 		&i.HashedPassword,
 		&i.IsChirpyRed,
+		&i.Username,
+		&i.DmPolicy,
+		&i.Role,
 	)
 	return i, err
 }
 
-const updateUser = `-- name: UpdateUser :one
-UPDATE users SET email = $2, hashed_password = $3, updated_at = NOW()
+const getUsersByUsernames = `-- name: GetUsersByUsernames :many
+SELECT id, created_at, updated_at, email, hashed_password, is_chirpy_red, username, dm_policy, role FROM users
+WHERE LOWER(username) = ANY($1::text[])
+`
+
+func (q *Queries) GetUsersByUsernames(ctx context.Context, usernames []string) ([]User, error) {
+	rows, err := q.db.QueryContext(ctx, getUsersByUsernames, pq.Array(usernames))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []User
+	for rows.Next() {
+		var i User
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Email,
+			&i.HashedPassword,
+			&i.IsChirpyRed,
+			&i.Username,
+			&i.DmPolicy,
+			&i.Role,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listUsersWithRolesAsc = `-- name: ListUsersWithRolesAsc :many
+SELECT id, created_at, updated_at, email, hashed_password, is_chirpy_red, username, dm_policy, role FROM users
+WHERE role != 'user'
+AND (
+    $1::timestamp IS NULL
+    OR (created_at, id) > ($1::timestamp, $2::uuid)
+)
+ORDER BY created_at ASC, id ASC
+LIMIT $3
+`
+
+type ListUsersWithRolesAscParams struct {
+	CursorCreatedAt sql.NullTime
+	CursorID        uuid.NullUUID
+	RowLimit        int32
+}
+
+// Everyone with a role above user
+func (q *Queries) ListUsersWithRolesAsc(ctx context.Context, arg ListUsersWithRolesAscParams) ([]User, error) {
+	rows, err := q.db.QueryContext(ctx, listUsersWithRolesAsc, arg.CursorCreatedAt, arg.CursorID, arg.RowLimit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []User
+	for rows.Next() {
+		var i User
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Email,
+			&i.HashedPassword,
+			&i.IsChirpyRed,
+			&i.Username,
+			&i.DmPolicy,
+			&i.Role,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listUsersWithRolesDesc = `-- name: ListUsersWithRolesDesc :many
+SELECT id, created_at, updated_at, email, hashed_password, is_chirpy_red, username, dm_policy, role FROM users
+WHERE role != 'user'
+AND (
+    $1::timestamp IS NULL
+    OR (created_at, id) < ($1::timestamp, $2::uuid)
+)
+ORDER BY created_at DESC, id DESC
+LIMIT $3
+`
+
+type ListUsersWithRolesDescParams struct {
+	CursorCreatedAt sql.NullTime
+	CursorID        uuid.NullUUID
+	RowLimit        int32
+}
+
+// Everyone with a role above user
+func (q *Queries) ListUsersWithRolesDesc(ctx context.Context, arg ListUsersWithRolesDescParams) ([]User, error) {
+	rows, err := q.db.QueryContext(ctx, listUsersWithRolesDesc, arg.CursorCreatedAt, arg.CursorID, arg.RowLimit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []User
+	for rows.Next() {
+		var i User
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Email,
+			&i.HashedPassword,
+			&i.IsChirpyRed,
+			&i.Username,
+			&i.DmPolicy,
+			&i.Role,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const setUserRole = `-- name: SetUserRole :one
+UPDATE users
+SET role = $2, updated_at = NOW()
 WHERE id = $1
-RETURNING id, created_at, updated_at, email, hashed_password, is_chirpy_red
+RETURNING id, created_at, updated_at, email, hashed_password, is_chirpy_red, username, dm_policy, role
+`
+
+type SetUserRoleParams struct {
+	ID   uuid.UUID
+	Role string
+}
+
+func (q *Queries) SetUserRole(ctx context.Context, arg SetUserRoleParams) (User, error) {
+	row := q.db.QueryRowContext(ctx, setUserRole, arg.ID, arg.Role)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Email,
+		&i.HashedPassword,
+		&i.IsChirpyRed,
+		&i.Username,
+		&i.DmPolicy,
+		&i.Role,
+	)
+	return i, err
+}
+
+const updateUser = `-- name: UpdateUser :one
+UPDATE users SET email = $1, hashed_password = $2, username = COALESCE($3, username), dm_policy = COALESCE($4, dm_policy), updated_at = NOW()
+WHERE id = $5
+RETURNING id, created_at, updated_at, email, hashed_password, is_chirpy_red, username, dm_policy, role
 `
 
 type UpdateUserParams struct {
-	ID             uuid.UUID
 	Email          string
 	HashedPassword string
+	Username       sql.NullString
+	DmPolicy       sql.NullString
+	ID             uuid.UUID
 }
 
 func (q *Queries) UpdateUser(ctx context.Context, arg UpdateUserParams) (User, error) {
-	row := q.db.QueryRowContext(ctx, updateUser, arg.ID, arg.Email, arg.HashedPassword)
+	row := q.db.QueryRowContext(ctx, updateUser,
+		arg.Email,
+		arg.HashedPassword,
+		arg.Username,
+		arg.DmPolicy,
+		arg.ID,
+	)
 	var i User
 	err := row.Scan(
 		&i.ID,
@@ -83,14 +310,39 @@ func (q *Queries) UpdateUser(ctx context.Context, arg UpdateUserParams) (User, e
 		&i.Email,
 		&i.HashedPassword,
 		&i.IsChirpyRed,
+		&i.Username,
+		&i.DmPolicy,
+		&i.Role,
 	)
 	return i, err
 }
 
+const updateUserPasswordHash = `-- name: UpdateUserPasswordHash :execrows
+UPDATE users SET hashed_password = $1
+WHERE id = $2
+AND hashed_password = $3
+`
+
+type UpdateUserPasswordHashParams struct {
+	NewHash string
+	ID      uuid.UUID
+	OldHash string
+}
+
+// Swaps in a rehashed password, unless the password changed in the meantime
+func (q *Queries) UpdateUserPasswordHash(ctx context.Context, arg UpdateUserPasswordHashParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, updateUserPasswordHash, arg.NewHash, arg.ID, arg.OldHash)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
 const upgradeToChirpyRed = `-- name: UpgradeToChirpyRed :one
-UPDATE users SET is_chirpy_red = true, updated_at = NOW()
+UPDATE users 
+SET is_chirpy_red = true, updated_at = NOW()
 WHERE id = $1
-RETURNING id, created_at, updated_at, email, hashed_password, is_chirpy_red
+RETURNING id, created_at, updated_at, email, hashed_password, is_chirpy_red, username, dm_policy, role
 `
 
 func (q *Queries) UpgradeToChirpyRed(ctx context.Context, id uuid.UUID) (User, error) {
@@ -103,6 +355,9 @@ func (q *Queries) UpgradeToChirpyRed(ctx context.Context, id uuid.UUID) (User, e
 		&i.Email,
 		&i.HashedPassword,
 		&i.IsChirpyRed,
+		&i.Username,
+		&i.DmPolicy,
+		&i.Role,
 	)
 	return i, err
 }
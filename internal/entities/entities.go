@@ -0,0 +1,69 @@
+package entities
+
+import (
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// A hashtag is # followed by letters, digits and underscores, with at least
+// one letter so "#1" isn't a tag. It can't follow a word character, which
+// keeps things like URL fragments ("page#section") out.
+var hashtagRegex = regexp.MustCompile(`(?:^|[^\p{L}\p{N}_&])#([\p{L}\p{N}_]*\p{L}[\p{L}\p{N}_]*)`)
+
+// Hashtags returns the normalized (lowercased) hashtags in body without the
+// leading #, each once, in the order they first appear
+func Hashtags(body string) []string {
+	tags := []string{}
+	seen := map[string]bool{}
+	for _, match := range hashtagRegex.FindAllStringSubmatch(body, -1) {
+		tag := strings.ToLower(match[1])
+		if seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		tags = append(tags, tag)
+	}
+	return tags
+}
+
+// NormalizeHashtag turns user input like "#GoLang" into the stored form "golang"
+func NormalizeHashtag(tag string) string {
+	return strings.ToLower(strings.TrimPrefix(tag, "#"))
+}
+
+// A username is 1 to 15 ASCII letters, digits or underscores
+var usernameRegex = regexp.MustCompile(`^[A-Za-z0-9_]{1,15}$`)
+
+// A mention is @ followed by a username. Like hashtags it can't follow a word
+// character, so email addresses aren't mentions.
+var mentionRegex = regexp.MustCompile(`(?:^|[^\p{L}\p{N}_@])(@([A-Za-z0-9_]{1,15}))\b`)
+
+// Mention is an @username in a chirp body. Start and End are the offsets of
+// the whole "@username", counted in characters (Unicode code points) with End
+// exclusive, so clients can link it without re-parsing the body.
+type Mention struct {
+	Username string
+	Start    int
+	End      int
+}
+
+// ValidUsername reports whether s can be used as a username
+func ValidUsername(s string) bool {
+	return usernameRegex.MatchString(s)
+}
+
+// Mentions returns every @username in body in the order they appear.
+// Usernames keep the case they were written in.
+func Mentions(body string) []Mention {
+	mentions := []Mention{}
+	for _, match := range mentionRegex.FindAllStringSubmatchIndex(body, -1) {
+		start := utf8.RuneCountInString(body[:match[2]])
+		mentions = append(mentions, Mention{
+			Username: body[match[4]:match[5]],
+			Start:    start,
+			End:      start + utf8.RuneCountInString(body[match[2]:match[3]]),
+		})
+	}
+	return mentions
+}
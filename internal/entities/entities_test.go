@@ -0,0 +1,124 @@
+package entities
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestHashtags(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want []string
+	}{
+		{
+			name: "Single tag",
+			body: "Learning #golang today",
+			want: []string{"golang"},
+		},
+		{
+			name: "Tags are lowercased and deduplicated",
+			body: "#Go is great, #GO is fast, #go",
+			want: []string{"go"},
+		},
+		{
+			name: "Punctuation ends a tag",
+			body: "Shipping it! #launch, #release.",
+			want: []string{"launch", "release"},
+		},
+		{
+			name: "Numbers alone aren't tags",
+			body: "We're #1 and #2024goals",
+			want: []string{"2024goals"},
+		},
+		{
+			name: "Tags inside words are ignored",
+			body: "see example.com/page#section and a#b",
+			want: []string{},
+		},
+		{
+			name: "Unicode letters",
+			body: "#Café #日本",
+			want: []string{"café", "日本"},
+		},
+		{
+			name: "Cleaned words aren't tags",
+			body: "#**** nope",
+			want: []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Hashtags(tt.body)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Hashtags() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMentions(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want []Mention
+	}{
+		{
+			name: "Single mention",
+			body: "hi @alice!",
+			want: []Mention{{Username: "alice", Start: 3, End: 9}},
+		},
+		{
+			name: "Case is kept and repeats are kept",
+			body: "@Bob and @bob",
+			want: []Mention{{Username: "Bob", Start: 0, End: 4}, {Username: "bob", Start: 9, End: 13}},
+		},
+		{
+			name: "Offsets count characters, not bytes",
+			body: "café ☕ @carol",
+			want: []Mention{{Username: "carol", Start: 7, End: 13}},
+		},
+		{
+			name: "Email addresses aren't mentions",
+			body: "mail me at dave@example.com",
+			want: []Mention{},
+		},
+		{
+			name: "Too long to be a username",
+			body: "@abcdefghijklmnop",
+			want: []Mention{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Mentions(tt.body)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Mentions() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidUsername(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  bool
+	}{
+		{name: "Letters, digits and underscores", input: "chirp_fan_42", want: true},
+		{name: "Empty", input: "", want: false},
+		{name: "Too long", input: "abcdefghijklmnop", want: false},
+		{name: "Spaces", input: "chirp fan", want: false},
+		{name: "Leading @", input: "@chirp", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ValidUsername(tt.input); got != tt.want {
+				t.Errorf("ValidUsername(%q) = %v, want %v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
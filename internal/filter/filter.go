@@ -0,0 +1,226 @@
+package filter
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"sync/atomic"
+	"unicode"
+)
+
+// Action is what happens to text a filter matches
+type Action string
+
+const (
+	// ActionMask replaces the word with asterisks
+	ActionMask Action = "mask"
+	// ActionReject refuses the whole text
+	ActionReject Action = "reject"
+	// ActionFlag lets the text through but sends it to moderators
+	ActionFlag Action = "flag"
+)
+
+// What masked words are replaced with
+const mask = "****"
+
+var nameRegexp = regexp.MustCompile(`^[a-z0-9_-]{1,32}$`)
+
+// ValidAction reports whether s is one of the actions
+func ValidAction(s string) bool {
+	switch Action(s) {
+	case ActionMask, ActionReject, ActionFlag:
+		return true
+	}
+	return false
+}
+
+// ValidName reports whether s can name a filter: 1 to 32 lowercase letters,
+// numbers, underscores or dashes
+func ValidName(s string) bool {
+	return nameRegexp.MatchString(s)
+}
+
+// ValidWord reports whether s is a single word, which is all a word list
+// can match
+func ValidWord(s string) bool {
+	spans := words(s)
+	return len(spans) == 1 && spans[0] == [2]int{0, len(s)}
+}
+
+// Filter decides which words it's looking for. Words are passed through Fold
+// before Match sees them.
+type Filter interface {
+	Name() string
+	Action() Action
+	Match(word string) bool
+}
+
+// WordList is a Filter that matches any word on a list
+type WordList struct {
+	name   string
+	action Action
+	words  map[string]struct{}
+}
+
+// NewWordList makes a filter matching words, however they're cased or
+// accented
+func NewWordList(name string, action Action, words []string) *WordList {
+	l := &WordList{
+		name:   name,
+		action: action,
+		words:  map[string]struct{}{},
+	}
+	for _, word := range words {
+		folded := Fold(strings.TrimSpace(word))
+		if folded != "" {
+			l.words[folded] = struct{}{}
+		}
+	}
+	return l
+}
+
+func (l *WordList) Name() string {
+	return l.name
+}
+
+func (l *WordList) Action() Action {
+	return l.action
+}
+
+func (l *WordList) Match(word string) bool {
+	_, ok := l.words[word]
+	return ok
+}
+
+// Words returns the folded words on the list in order
+func (l *WordList) Words() []string {
+	words := make([]string, 0, len(l.words))
+	for word := range l.words {
+		words = append(words, word)
+	}
+	sort.Strings(words)
+	return words
+}
+
+// Fold normalizes a word for matching. Case is folded, so "KERFUFFLE" and
+// "Kerfuffle" are the same word, and combining marks are dropped, so a word
+// typed with separate accents matches the plain letters.
+func Fold(word string) string {
+	var b strings.Builder
+	for _, r := range word {
+		if unicode.Is(unicode.Mn, r) {
+			continue
+		}
+		// Going through upper case first folds letters like ſ and ς that
+		// only lower case to themselves
+		b.WriteRune(unicode.ToLower(unicode.ToUpper(r)))
+	}
+	return b.String()
+}
+
+// Match is a word a filter caught
+type Match struct {
+	Filter string
+	Action Action
+	Word   string
+}
+
+// RejectedError is returned for text a reject filter matched
+type RejectedError struct {
+	Match Match
+}
+
+func (e *RejectedError) Error() string {
+	return fmt.Sprintf("rejected by the %s filter", e.Match.Filter)
+}
+
+// Result is text that made it through the pipeline
+type Result struct {
+	// Text with masked words replaced
+	Text string
+	// Flagged is every match from a flag filter
+	Flagged []Match
+}
+
+// Pipeline runs text through a set of filters. The set can be replaced while
+// the pipeline is in use.
+type Pipeline struct {
+	filters atomic.Pointer[[]Filter]
+}
+
+// NewPipeline makes a pipeline running filters
+func NewPipeline(filters ...Filter) *Pipeline {
+	p := &Pipeline{}
+	p.Replace(filters)
+	return p
+}
+
+// Replace swaps in a new set of filters. Text already going through the
+// pipeline finishes with the old set.
+func (p *Pipeline) Replace(filters []Filter) {
+	p.filters.Store(&filters)
+}
+
+// Filters returns the filters the pipeline is running
+func (p *Pipeline) Filters() []Filter {
+	return *p.filters.Load()
+}
+
+// Apply runs every filter over each word of text. Words are runs of letters,
+// numbers and marks, so punctuation around a word doesn't hide it. A reject
+// match returns a *RejectedError.
+func (p *Pipeline) Apply(text string) (Result, error) {
+	filters := p.Filters()
+	result := Result{}
+
+	var b strings.Builder
+	last := 0
+	for _, span := range words(text) {
+		word := text[span[0]:span[1]]
+		folded := Fold(word)
+		masked := false
+		for _, f := range filters {
+			if !f.Match(folded) {
+				continue
+			}
+			match := Match{Filter: f.Name(), Action: f.Action(), Word: word}
+			switch f.Action() {
+			case ActionReject:
+				return Result{}, &RejectedError{Match: match}
+			case ActionMask:
+				masked = true
+			case ActionFlag:
+				result.Flagged = append(result.Flagged, match)
+			}
+		}
+		if masked {
+			b.WriteString(text[last:span[0]])
+			b.WriteString(mask)
+			last = span[1]
+		}
+	}
+	b.WriteString(text[last:])
+	result.Text = b.String()
+	return result, nil
+}
+
+// words returns the start and end byte offsets of each word in text
+func words(text string) [][2]int {
+	spans := [][2]int{}
+	start := -1
+	for i, r := range text {
+		inWord := unicode.IsLetter(r) || unicode.IsNumber(r) || unicode.IsMark(r)
+		if inWord && start < 0 {
+			start = i
+		}
+		if !inWord && start >= 0 {
+			spans = append(spans, [2]int{start, i})
+			start = -1
+		}
+	}
+	if start >= 0 {
+		spans = append(spans, [2]int{start, len(text)})
+	}
+	return spans
+}
@@ -0,0 +1,201 @@
+package filter
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestApply(t *testing.T) {
+	pipeline := NewPipeline(
+		NewWordList("profanity", ActionMask, []string{"kerfuffle", "sharbert", "fornax"}),
+		NewWordList("slurs", ActionReject, []string{"blorp"}),
+		NewWordList("spam", ActionFlag, []string{"crypto"}),
+	)
+
+	tests := []struct {
+		name        string
+		input       string
+		wantText    string
+		wantFlagged []Match
+		wantReject  bool
+	}{
+		{name: "Clean", input: "I had something interesting for breakfast", wantText: "I had something interesting for breakfast"},
+		{name: "Masked", input: "This is a kerfuffle opinion", wantText: "This is a **** opinion"},
+		{name: "Case folded", input: "Sharbert! FORNAX.", wantText: "****! ****."},
+		{name: "Punctuation kept", input: "(kerfuffle), fornax?!", wantText: "(****), ****?!"},
+		{name: "Inside another word", input: "kerfufflement", wantText: "kerfufflement"},
+		{name: "Combining accent", input: "Kerfu\u0301ffle", wantText: "****"},
+		{name: "Rejected", input: "what a BLORP.", wantReject: true},
+		{
+			name:        "Flagged",
+			input:       "buy Crypto now, kerfuffle",
+			wantText:    "buy Crypto now, ****",
+			wantFlagged: []Match{{Filter: "spam", Action: ActionFlag, Word: "Crypto"}},
+		},
+		{name: "Empty", input: "", wantText: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := pipeline.Apply(tt.input)
+			var rejected *RejectedError
+			if tt.wantReject {
+				if !errors.As(err, &rejected) {
+					t.Fatalf("Apply(%q) error = %v, want a RejectedError", tt.input, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Apply(%q) error = %v", tt.input, err)
+			}
+			if result.Text != tt.wantText {
+				t.Errorf("Apply(%q) text = %q, want %q", tt.input, result.Text, tt.wantText)
+			}
+			if !reflect.DeepEqual(result.Flagged, tt.wantFlagged) {
+				t.Errorf("Apply(%q) flagged = %v, want %v", tt.input, result.Flagged, tt.wantFlagged)
+			}
+		})
+	}
+}
+
+func TestReplace(t *testing.T) {
+	pipeline := NewPipeline(NewWordList("profanity", ActionMask, []string{"kerfuffle"}))
+	pipeline.Replace([]Filter{NewWordList("profanity", ActionMask, []string{"fornax"})})
+
+	result, err := pipeline.Apply("kerfuffle fornax")
+	if err != nil {
+		t.Fatalf("Apply error = %v", err)
+	}
+	if want := "kerfuffle ****"; result.Text != want {
+		t.Errorf("Apply text = %q, want %q", result.Text, want)
+	}
+}
+
+func TestValidWord(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  bool
+	}{
+		{name: "Word", input: "kerfuffle", want: true},
+		{name: "Accented", input: "fo\u0308rnax", want: true},
+		{name: "Number", input: "1337", want: true},
+		{name: "Two words", input: "kerfuffle sharbert", want: false},
+		{name: "Punctuation", input: "kerfuffle!", want: false},
+		{name: "Apostrophe", input: "don't", want: false},
+		{name: "Empty", input: "", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ValidWord(tt.input); got != tt.want {
+				t.Errorf("ValidWord(%q) = %v, want %v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFold(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{name: "Lower", input: "fornax", want: "fornax"},
+		{name: "Upper", input: "FORNAX", want: "fornax"},
+		{name: "Long s", input: "ſharbert", want: "sharbert"},
+		{name: "Final sigma", input: "Σος", want: "σοσ"},
+		{name: "Combining marks", input: "fo\u0308rnax", want: "fornax"},
+		{name: "Precomposed accent kept", input: "f\u00f6rnax", want: "f\u00f6rnax"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Fold(tt.input); got != tt.want {
+				t.Errorf("Fold(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLength(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  int
+	}{
+		{name: "Empty", input: "", want: 0},
+		{name: "ASCII", input: "hello", want: 5},
+		{name: "Accented", input: "café", want: 4},
+		{name: "Combining accent", input: "cafe\u0301", want: 4},
+		{name: "Emoji", input: "\U0001F600\U0001F600", want: 2},
+		{name: "Skin tone", input: "\U0001F44D\U0001F3FD", want: 1},
+		{name: "Variation selector", input: "\u2764\ufe0f", want: 1},
+		{name: "ZWJ family", input: "\U0001F468\u200d\U0001F469\u200d\U0001F467", want: 1},
+		{name: "Flags", input: "\U0001F1EC\U0001F1E7\U0001F1EB\U0001F1F7", want: 2},
+		{name: "Odd flag letter", input: "\U0001F1EC\U0001F1E7\U0001F1EB", want: 2},
+		{name: "CRLF", input: "a\r\nb", want: 3},
+		{name: "Hangul jamo", input: "\u1100\u1161\u11a8", want: 1},
+		{name: "Hangul syllables", input: "한글", want: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Length(tt.input); got != tt.want {
+				t.Errorf("Length(%q) = %d, want %d", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    map[string][]string
+		wantErr bool
+	}{
+		{
+			name: "Sections",
+			input: `# word lists
+[profanity mask]
+Kerfuffle
+sharbert
+
+[spam flag]
+crypto
+`,
+			want: map[string][]string{
+				"profanity": {"kerfuffle", "sharbert"},
+				"spam":      {"crypto"},
+			},
+		},
+		{name: "Empty", input: "", want: map[string][]string{}},
+		{name: "Word before section", input: "kerfuffle\n[profanity mask]", wantErr: true},
+		{name: "Unknown action", input: "[profanity delete]", wantErr: true},
+		{name: "Missing action", input: "[profanity]", wantErr: true},
+		{name: "Bad name", input: "[Profanity mask]", wantErr: true},
+		{name: "Phrase", input: "[profanity mask]\nkerfuffle sharbert", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filters, err := Parse(strings.NewReader(tt.input))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Parse() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			got := map[string][]string{}
+			for _, f := range filters {
+				got[f.Name()] = f.(*WordList).Words()
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Parse() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
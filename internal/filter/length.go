@@ -0,0 +1,108 @@
+package filter
+
+import "unicode"
+
+const zeroWidthJoiner = '\u200d'
+
+// Length counts the characters a reader would see rather than bytes or code
+// points, so an emoji built from several code points, a flag or a letter with
+// separate accents each count once. It follows the parts of Unicode's
+// grapheme cluster rules that come up in chirps: CRLF, combining marks,
+// variation selectors, skin tones, emoji joined with ZWJ, flags and Hangul.
+func Length(s string) int {
+	n := 0
+	var prev rune
+	regionalIndicators := 0
+	for i, r := range s {
+		if i == 0 || !extendsCluster(prev, r, regionalIndicators) {
+			n++
+		}
+		if isRegionalIndicator(r) {
+			regionalIndicators++
+		} else {
+			regionalIndicators = 0
+		}
+		prev = r
+	}
+	return n
+}
+
+// extendsCluster reports whether r belongs to the same character as prev.
+// regionalIndicators is how many flag letters came right before r.
+func extendsCluster(prev, r rune, regionalIndicators int) bool {
+	switch {
+	case prev == '\r' && r == '\n':
+		return true
+	case prev == '\r' || prev == '\n' || r == '\r' || r == '\n':
+		return false
+	case isExtend(r) || r == zeroWidthJoiner:
+		return true
+	case prev == zeroWidthJoiner:
+		return true
+	case isRegionalIndicator(r):
+		// Flags are pairs of regional indicators
+		return regionalIndicators%2 == 1
+	}
+	return hangulJoins(prev, r)
+}
+
+// isExtend reports whether r only ever modifies the character before it
+func isExtend(r rune) bool {
+	switch {
+	case unicode.In(r, unicode.Mn, unicode.Me, unicode.Mc):
+		return true
+	case r >= 0xfe00 && r <= 0xfe0f, r >= 0xe0100 && r <= 0xe01ef: // variation selectors
+		return true
+	case r >= 0x1f3fb && r <= 0x1f3ff: // skin tones
+		return true
+	case r >= 0xe0020 && r <= 0xe007f: // tags, as in subdivision flags
+		return true
+	}
+	return false
+}
+
+func isRegionalIndicator(r rune) bool {
+	return r >= 0x1f1e6 && r <= 0x1f1ff
+}
+
+type hangulType int
+
+const (
+	hangulNone hangulType = iota
+	hangulL
+	hangulV
+	hangulT
+	hangulLV
+	hangulLVT
+)
+
+func hangulTypeOf(r rune) hangulType {
+	switch {
+	case r >= 0x1100 && r <= 0x115f, r >= 0xa960 && r <= 0xa97c:
+		return hangulL
+	case r >= 0x1160 && r <= 0x11a7, r >= 0xd7b0 && r <= 0xd7c6:
+		return hangulV
+	case r >= 0x11a8 && r <= 0x11ff, r >= 0xd7cb && r <= 0xd7fb:
+		return hangulT
+	case r >= 0xac00 && r <= 0xd7a3:
+		if (r-0xac00)%28 == 0 {
+			return hangulLV
+		}
+		return hangulLVT
+	}
+	return hangulNone
+}
+
+// hangulJoins reports whether two Hangul jamo or syllables make one syllable
+func hangulJoins(prev, r rune) bool {
+	p, c := hangulTypeOf(prev), hangulTypeOf(r)
+	switch p {
+	case hangulL:
+		return c == hangulL || c == hangulV || c == hangulLV || c == hangulLVT
+	case hangulLV, hangulV:
+		return c == hangulV || c == hangulT
+	case hangulLVT, hangulT:
+		return c == hangulT
+	}
+	return false
+}
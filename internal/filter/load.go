@@ -0,0 +1,93 @@
+package filter
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// Parse reads word lists written like this:
+//
+//	# Lines starting with # are comments
+//	[profanity mask]
+//	kerfuffle
+//	sharbert
+//
+//	[spam flag]
+//	crypto
+//
+// Each section starts with the filter's name and action, followed by its
+// words, one per line.
+func Parse(r io.Reader) ([]Filter, error) {
+	filters := []Filter{}
+	var name string
+	var action Action
+	var words []string
+	inSection := false
+
+	finish := func() {
+		if inSection {
+			filters = append(filters, NewWordList(name, action, words))
+		}
+	}
+
+	scanner := bufio.NewScanner(r)
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		if strings.HasPrefix(line, "[") {
+			if !strings.HasSuffix(line, "]") {
+				return nil, fmt.Errorf("line %d: section header is missing ]", lineNumber)
+			}
+			fields := strings.Fields(line[1 : len(line)-1])
+			if len(fields) != 2 {
+				return nil, fmt.Errorf("line %d: section header should be [name action]", lineNumber)
+			}
+			if !ValidName(fields[0]) {
+				return nil, fmt.Errorf("line %d: invalid filter name %q", lineNumber, fields[0])
+			}
+			if !ValidAction(fields[1]) {
+				return nil, fmt.Errorf("line %d: unknown action %q", lineNumber, fields[1])
+			}
+			finish()
+			name, action, words = fields[0], Action(fields[1]), nil
+			inSection = true
+			continue
+		}
+
+		if !inSection {
+			return nil, fmt.Errorf("line %d: word outside of a section", lineNumber)
+		}
+		if !ValidWord(line) {
+			return nil, fmt.Errorf("line %d: %q isn't a single word", lineNumber, line)
+		}
+		words = append(words, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	finish()
+	return filters, nil
+}
+
+// LoadFile reads word lists from a file in the format Parse expects
+func LoadFile(path string) ([]Filter, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	filters, err := Parse(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return filters, nil
+}
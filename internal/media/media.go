@@ -0,0 +1,172 @@
+package media
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/draw"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"net/http"
+)
+
+const (
+	// MaxBytes is the largest file that can be uploaded
+	MaxBytes = 5 << 20
+	// MaxPixels stops small files that decode into huge images
+	MaxPixels = 40_000_000
+	// ThumbnailSize is the longest side of a thumbnail
+	ThumbnailSize = 400
+	// Quality JPEGs are saved at
+	jpegQuality = 90
+)
+
+var (
+	ErrTooLarge        = errors.New("Image is too large")
+	ErrTooManyPixels   = errors.New("Image dimensions are too large")
+	ErrUnsupportedType = errors.New("Only JPEG, PNG and GIF images are supported")
+	ErrInvalidImage    = errors.New("Couldn't read image")
+)
+
+// Image is an encoded image ready to store
+type Image struct {
+	ContentType string
+	Data        []byte
+	Width       int
+	Height      int
+}
+
+// Ext is the file extension for the image's type
+func (i Image) Ext() string {
+	switch i.ContentType {
+	case "image/jpeg":
+		return ".jpg"
+	case "image/png":
+		return ".png"
+	case "image/gif":
+		return ".gif"
+	}
+	return ""
+}
+
+// Process checks an upload really is an image we accept, then re-encodes it
+// and makes a thumbnail. Re-encoding drops EXIF and every other kind of
+// metadata, like where a photo was taken. JPEGs are turned the right way up
+// first, since the orientation goes with the EXIF.
+func Process(r io.Reader) (original, thumbnail Image, err error) {
+	data, err := io.ReadAll(io.LimitReader(r, MaxBytes+1))
+	if err != nil {
+		return Image{}, Image{}, err
+	}
+	if len(data) > MaxBytes {
+		return Image{}, Image{}, ErrTooLarge
+	}
+
+	// Sniffed rather than trusting the upload's Content-Type or file name
+	contentType := http.DetectContentType(data)
+	switch contentType {
+	case "image/jpeg", "image/png", "image/gif":
+	default:
+		return Image{}, Image{}, ErrUnsupportedType
+	}
+
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return Image{}, Image{}, ErrInvalidImage
+	}
+	if config.Width <= 0 || config.Height <= 0 {
+		return Image{}, Image{}, ErrInvalidImage
+	}
+	if config.Width*config.Height > MaxPixels {
+		return Image{}, Image{}, ErrTooManyPixels
+	}
+
+	switch contentType {
+	case "image/gif":
+		return processGIF(data)
+	case "image/jpeg":
+		img, err := jpeg.Decode(bytes.NewReader(data))
+		if err != nil {
+			return Image{}, Image{}, ErrInvalidImage
+		}
+		img = orient(img, jpegOrientation(data))
+		original, err = encode(img, contentType)
+		if err != nil {
+			return Image{}, Image{}, err
+		}
+		thumbnail, err = encode(fit(img, ThumbnailSize), contentType)
+		return original, thumbnail, err
+	default:
+		img, err := png.Decode(bytes.NewReader(data))
+		if err != nil {
+			return Image{}, Image{}, ErrInvalidImage
+		}
+		original, err = encode(img, contentType)
+		if err != nil {
+			return Image{}, Image{}, err
+		}
+		thumbnail, err = encode(fit(img, ThumbnailSize), contentType)
+		return original, thumbnail, err
+	}
+}
+
+// processGIF keeps every frame of an animation. The thumbnail is a PNG of the
+// first frame.
+func processGIF(data []byte) (original, thumbnail Image, err error) {
+	g, err := gif.DecodeAll(bytes.NewReader(data))
+	if err != nil || len(g.Image) == 0 {
+		return Image{}, Image{}, ErrInvalidImage
+	}
+	if len(g.Image)*g.Config.Width*g.Config.Height > MaxPixels {
+		return Image{}, Image{}, ErrTooManyPixels
+	}
+
+	// EncodeAll only writes frames and the loop count, so comments and
+	// application extensions are left behind
+	var buf bytes.Buffer
+	err = gif.EncodeAll(&buf, &gif.GIF{
+		Image:     g.Image,
+		Delay:     g.Delay,
+		LoopCount: g.LoopCount,
+		Disposal:  g.Disposal,
+		Config:    g.Config,
+	})
+	if err != nil {
+		return Image{}, Image{}, err
+	}
+	original = Image{
+		ContentType: "image/gif",
+		Data:        buf.Bytes(),
+		Width:       g.Config.Width,
+		Height:      g.Config.Height,
+	}
+
+	// The first frame can be smaller than the canvas
+	first := image.NewRGBA(image.Rect(0, 0, g.Config.Width, g.Config.Height))
+	draw.Draw(first, g.Image[0].Bounds(), g.Image[0], g.Image[0].Bounds().Min, draw.Over)
+	thumbnail, err = encode(fit(first, ThumbnailSize), "image/png")
+	return original, thumbnail, err
+}
+
+func encode(img image.Image, contentType string) (Image, error) {
+	var buf bytes.Buffer
+	var err error
+	switch contentType {
+	case "image/jpeg":
+		err = jpeg.Encode(&buf, img, &jpeg.Options{Quality: jpegQuality})
+	default:
+		contentType = "image/png"
+		err = png.Encode(&buf, img)
+	}
+	if err != nil {
+		return Image{}, err
+	}
+	return Image{
+		ContentType: contentType,
+		Data:        buf.Bytes(),
+		Width:       img.Bounds().Dx(),
+		Height:      img.Bounds().Dy(),
+	}, nil
+}
@@ -0,0 +1,222 @@
+package media
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"image"
+	"image/color"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"strings"
+	"testing"
+)
+
+// testImage is red on the left half and blue on the right
+func testImage(width, height int) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			c := color.RGBA{R: 255, A: 255}
+			if x >= width/2 {
+				c = color.RGBA{B: 255, A: 255}
+			}
+			img.Set(x, y, c)
+		}
+	}
+	return img
+}
+
+// withOrientation adds an EXIF segment with an orientation tag to a JPEG
+func withOrientation(jpegData []byte, orientation uint16) []byte {
+	tiff := []byte("MM\x00\x2a\x00\x00\x00\x08")
+	tiff = binary.BigEndian.AppendUint16(tiff, 1) // one entry
+	tiff = binary.BigEndian.AppendUint16(tiff, 0x0112)
+	tiff = binary.BigEndian.AppendUint16(tiff, 3)
+	tiff = binary.BigEndian.AppendUint32(tiff, 1)
+	tiff = binary.BigEndian.AppendUint16(tiff, orientation)
+	tiff = append(tiff, 0, 0, 0, 0, 0, 0)
+	payload := append([]byte("Exif\x00\x00"), tiff...)
+
+	segment := []byte{0xff, 0xe1}
+	segment = binary.BigEndian.AppendUint16(segment, uint16(len(payload)+2))
+	segment = append(segment, payload...)
+
+	out := append([]byte{}, jpegData[:2]...)
+	out = append(out, segment...)
+	return append(out, jpegData[2:]...)
+}
+
+func encodeJPEG(t *testing.T, img image.Image) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, img, nil); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func encodePNG(t *testing.T, img image.Image) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func encodeGIF(t *testing.T, frames int) []byte {
+	t.Helper()
+	palette := color.Palette{color.Black, color.White}
+	g := &gif.GIF{}
+	for i := 0; i < frames; i++ {
+		g.Image = append(g.Image, image.NewPaletted(image.Rect(0, 0, 20, 10), palette))
+		g.Delay = append(g.Delay, 10)
+	}
+	var buf bytes.Buffer
+	if err := gif.EncodeAll(&buf, g); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestProcess(t *testing.T) {
+	jpegData := encodeJPEG(t, testImage(800, 600))
+
+	tests := []struct {
+		name          string
+		input         []byte
+		wantType      string
+		wantWidth     int
+		wantHeight    int
+		wantThumbType string
+		wantThumbW    int
+		wantThumbH    int
+		wantErr       error
+	}{
+		{
+			name: "JPEG", input: jpegData,
+			wantType: "image/jpeg", wantWidth: 800, wantHeight: 600,
+			wantThumbType: "image/jpeg", wantThumbW: 400, wantThumbH: 300,
+		},
+		{
+			name: "JPEG on its side", input: withOrientation(jpegData, 6),
+			wantType: "image/jpeg", wantWidth: 600, wantHeight: 800,
+			wantThumbType: "image/jpeg", wantThumbW: 300, wantThumbH: 400,
+		},
+		{
+			name: "Small PNG", input: encodePNG(t, testImage(100, 50)),
+			wantType: "image/png", wantWidth: 100, wantHeight: 50,
+			wantThumbType: "image/png", wantThumbW: 100, wantThumbH: 50,
+		},
+		{
+			name: "Animated GIF", input: encodeGIF(t, 3),
+			wantType: "image/gif", wantWidth: 20, wantHeight: 10,
+			wantThumbType: "image/png", wantThumbW: 20, wantThumbH: 10,
+		},
+		{name: "Text", input: []byte("definitely not an image"), wantErr: ErrUnsupportedType},
+		{name: "Truncated PNG", input: encodePNG(t, testImage(100, 50))[:40], wantErr: ErrInvalidImage},
+		{name: "Too large", input: append(jpegData, make([]byte, MaxBytes)...), wantErr: ErrTooLarge},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			original, thumbnail, err := Process(bytes.NewReader(tt.input))
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("Process() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Process() error = %v", err)
+			}
+			if original.ContentType != tt.wantType || original.Width != tt.wantWidth || original.Height != tt.wantHeight {
+				t.Errorf("original = %s %dx%d, want %s %dx%d", original.ContentType, original.Width, original.Height, tt.wantType, tt.wantWidth, tt.wantHeight)
+			}
+			if thumbnail.ContentType != tt.wantThumbType || thumbnail.Width != tt.wantThumbW || thumbnail.Height != tt.wantThumbH {
+				t.Errorf("thumbnail = %s %dx%d, want %s %dx%d", thumbnail.ContentType, thumbnail.Width, thumbnail.Height, tt.wantThumbType, tt.wantThumbW, tt.wantThumbH)
+			}
+			if strings.Contains(string(original.Data), "Exif") {
+				t.Errorf("original still has EXIF")
+			}
+			config, _, err := image.DecodeConfig(bytes.NewReader(original.Data))
+			if err != nil || config.Width != original.Width || config.Height != original.Height {
+				t.Errorf("encoded original is %dx%d (error %v), want %dx%d", config.Width, config.Height, err, original.Width, original.Height)
+			}
+		})
+	}
+}
+
+func TestJPEGOrientation(t *testing.T) {
+	jpegData := encodeJPEG(t, testImage(8, 8))
+	for orientation := uint16(1); orientation <= 8; orientation++ {
+		if got := jpegOrientation(withOrientation(jpegData, orientation)); got != int(orientation) {
+			t.Errorf("jpegOrientation() = %d, want %d", got, orientation)
+		}
+	}
+	if got := jpegOrientation(jpegData); got != 1 {
+		t.Errorf("jpegOrientation() without EXIF = %d, want 1", got)
+	}
+	if got := jpegOrientation(withOrientation(jpegData, 42)); got != 1 {
+		t.Errorf("jpegOrientation() with a bad value = %d, want 1", got)
+	}
+}
+
+func TestOrient(t *testing.T) {
+	// Red on the left, blue on the right
+	img := testImage(4, 2)
+	red := color.RGBA{R: 255, A: 255}
+	blue := color.RGBA{B: 255, A: 255}
+
+	tests := []struct {
+		name        string
+		orientation int
+		wantWidth   int
+		wantHeight  int
+		// Colour of the top left pixel afterwards
+		wantTopLeft color.RGBA
+	}{
+		{name: "Upright", orientation: 1, wantWidth: 4, wantHeight: 2, wantTopLeft: red},
+		{name: "Mirrored", orientation: 2, wantWidth: 4, wantHeight: 2, wantTopLeft: blue},
+		{name: "Upside down", orientation: 3, wantWidth: 4, wantHeight: 2, wantTopLeft: blue},
+		{name: "Turned clockwise", orientation: 6, wantWidth: 2, wantHeight: 4, wantTopLeft: red},
+		{name: "Turned anticlockwise", orientation: 8, wantWidth: 2, wantHeight: 4, wantTopLeft: blue},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := orient(img, tt.orientation)
+			if got.Bounds().Dx() != tt.wantWidth || got.Bounds().Dy() != tt.wantHeight {
+				t.Fatalf("orient() is %dx%d, want %dx%d", got.Bounds().Dx(), got.Bounds().Dy(), tt.wantWidth, tt.wantHeight)
+			}
+			if c := color.RGBAModel.Convert(got.At(0, 0)).(color.RGBA); c != tt.wantTopLeft {
+				t.Errorf("top left = %v, want %v", c, tt.wantTopLeft)
+			}
+		})
+	}
+}
+
+func TestFitSize(t *testing.T) {
+	tests := []struct {
+		name          string
+		width, height int
+		wantW, wantH  int
+	}{
+		{name: "Already fits", width: 300, height: 200, wantW: 300, wantH: 200},
+		{name: "Landscape", width: 1600, height: 900, wantW: 400, wantH: 225},
+		{name: "Portrait", width: 900, height: 1600, wantW: 225, wantH: 400},
+		{name: "Square", width: 1000, height: 1000, wantW: 400, wantH: 400},
+		{name: "Very thin", width: 10000, height: 2, wantW: 400, wantH: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w, h := fitSize(tt.width, tt.height, 400)
+			if w != tt.wantW || h != tt.wantH {
+				t.Errorf("fitSize(%d, %d) = %d, %d, want %d, %d", tt.width, tt.height, w, h, tt.wantW, tt.wantH)
+			}
+		})
+	}
+}
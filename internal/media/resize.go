@@ -0,0 +1,66 @@
+package media
+
+import (
+	"image"
+	"image/draw"
+)
+
+// fitSize scales width and height down to fit inside a size by size box,
+// keeping the aspect ratio. Images that already fit keep their size.
+func fitSize(width, height, size int) (int, int) {
+	if width <= size && height <= size {
+		return width, height
+	}
+	if width >= height {
+		return size, max(1, height*size/width)
+	}
+	return max(1, width*size/height), size
+}
+
+// fit shrinks img to fit inside a size by size box
+func fit(img image.Image, size int) image.Image {
+	bounds := img.Bounds()
+	width, height := fitSize(bounds.Dx(), bounds.Dy(), size)
+	if width == bounds.Dx() && height == bounds.Dy() {
+		return img
+	}
+	return resize(img, width, height)
+}
+
+// resize scales img down with a box filter: each pixel of the result is the
+// average of the pixels it covers in img
+func resize(img image.Image, width, height int) *image.RGBA {
+	bounds := img.Bounds()
+	src := image.NewRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+	draw.Draw(src, src.Bounds(), img, bounds.Min, draw.Src)
+	srcWidth, srcHeight := src.Bounds().Dx(), src.Bounds().Dy()
+
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		y0 := y * srcHeight / height
+		y1 := max(y0+1, (y+1)*srcHeight/height)
+		for x := 0; x < width; x++ {
+			x0 := x * srcWidth / width
+			x1 := max(x0+1, (x+1)*srcWidth/width)
+
+			var r, g, b, a, n int
+			for sy := y0; sy < y1; sy++ {
+				i := src.PixOffset(x0, sy)
+				for sx := x0; sx < x1; sx++ {
+					r += int(src.Pix[i])
+					g += int(src.Pix[i+1])
+					b += int(src.Pix[i+2])
+					a += int(src.Pix[i+3])
+					i += 4
+					n++
+				}
+			}
+			i := dst.PixOffset(x, y)
+			dst.Pix[i] = uint8(r / n)
+			dst.Pix[i+1] = uint8(g / n)
+			dst.Pix[i+2] = uint8(b / n)
+			dst.Pix[i+3] = uint8(a / n)
+		}
+	}
+	return dst
+}
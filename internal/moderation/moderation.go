@@ -0,0 +1,77 @@
+package moderation
+
+// Reason is the category a reporter picks
+type Reason string
+
+const (
+	ReasonSpam          Reason = "spam"
+	ReasonHarassment    Reason = "harassment"
+	ReasonHate          Reason = "hate"
+	ReasonViolence      Reason = "violence"
+	ReasonSexual        Reason = "sexual"
+	ReasonSelfHarm      Reason = "self_harm"
+	ReasonImpersonation Reason = "impersonation"
+	ReasonOther         Reason = "other"
+)
+
+// ReasonFilter is for reports a content filter files by itself. Reporters
+// can't pick it.
+const ReasonFilter Reason = "filter"
+
+// Reasons lists every reason in the order clients should offer them
+var Reasons = []Reason{
+	ReasonSpam,
+	ReasonHarassment,
+	ReasonHate,
+	ReasonViolence,
+	ReasonSexual,
+	ReasonSelfHarm,
+	ReasonImpersonation,
+	ReasonOther,
+}
+
+// Target is what a report is about
+type Target string
+
+const (
+	TargetChirp Target = "chirp"
+	TargetUser  Target = "user"
+)
+
+// Action is what a moderator decides to do about a report
+type Action string
+
+const (
+	// ActionHide keeps the chirp but takes it out of public view
+	ActionHide Action = "hide"
+	// ActionDelete deletes the chirp
+	ActionDelete Action = "delete"
+	// ActionDismiss closes the report without doing anything
+	ActionDismiss Action = "dismiss"
+	// ActionResolve closes a report about a user once it's been dealt with
+	ActionResolve Action = "resolve"
+)
+
+// ValidReason reports whether s is one of Reasons
+func ValidReason(s string) bool {
+	for _, reason := range Reasons {
+		if string(reason) == s {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidAction reports whether action can be taken on a report about target.
+// Only chirps can be hidden or deleted.
+func ValidAction(action string, target Target) bool {
+	switch Action(action) {
+	case ActionDismiss:
+		return true
+	case ActionHide, ActionDelete:
+		return target == TargetChirp
+	case ActionResolve:
+		return target == TargetUser
+	}
+	return false
+}
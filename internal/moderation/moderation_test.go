@@ -0,0 +1,54 @@
+package moderation
+
+import "testing"
+
+func TestValidReason(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  bool
+	}{
+		{name: "Spam", input: "spam", want: true},
+		{name: "Underscored", input: "self_harm", want: true},
+		{name: "Other", input: "other", want: true},
+		{name: "Wrong case", input: "Spam", want: false},
+		{name: "Filter only", input: "filter", want: false},
+		{name: "Unknown", input: "boring", want: false},
+		{name: "Empty", input: "", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ValidReason(tt.input); got != tt.want {
+				t.Errorf("ValidReason(%q) = %v, want %v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidAction(t *testing.T) {
+	tests := []struct {
+		name   string
+		action string
+		target Target
+		want   bool
+	}{
+		{name: "Hide chirp", action: "hide", target: TargetChirp, want: true},
+		{name: "Delete chirp", action: "delete", target: TargetChirp, want: true},
+		{name: "Dismiss chirp report", action: "dismiss", target: TargetChirp, want: true},
+		{name: "Resolve chirp report", action: "resolve", target: TargetChirp, want: false},
+		{name: "Hide user", action: "hide", target: TargetUser, want: false},
+		{name: "Delete user", action: "delete", target: TargetUser, want: false},
+		{name: "Dismiss user report", action: "dismiss", target: TargetUser, want: true},
+		{name: "Resolve user report", action: "resolve", target: TargetUser, want: true},
+		{name: "Unknown", action: "ban", target: TargetUser, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ValidAction(tt.action, tt.target); got != tt.want {
+				t.Errorf("ValidAction(%q, %q) = %v, want %v", tt.action, tt.target, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,127 @@
+package notifications
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"log"
+
+	"github.com/google/uuid"
+	"github.com/srinivassivaratri/Chirpy/internal/database"
+	"github.com/srinivassivaratri/Chirpy/internal/pubsub"
+)
+
+// Kind says what a notification is about
+type Kind string
+
+const (
+	// KindLike - someone liked one of your chirps
+	KindLike Kind = "like"
+	// KindReply - someone replied to one of your chirps
+	KindReply Kind = "reply"
+	// KindMention - someone mentioned you in a chirp
+	KindMention Kind = "mention"
+	// KindFollow - someone followed you
+	KindFollow Kind = "follow"
+	// KindChirpyRed - your Chirpy Red membership started
+	KindChirpyRed Kind = "chirpy_red"
+	// KindPollClosed - a poll on one of your chirps closed
+	KindPollClosed Kind = "poll_closed"
+)
+
+// How many notifications can wait to be saved before new ones are dropped
+const queueSize = 1000
+
+// Channel is the pubsub channel every saved notification is announced on
+const Channel = "notifications"
+
+// Announcement is the payload published on Channel
+type Announcement struct {
+	ID     uuid.UUID `json:"id"`
+	UserID uuid.UUID `json:"user_id"`
+}
+
+// Notification is sent to UserID. ActorID is whoever caused it, if anyone,
+// and ChirpID the chirp it's about, if any.
+type Notification struct {
+	UserID  uuid.UUID
+	ActorID uuid.NullUUID
+	Kind    Kind
+	ChirpID uuid.NullUUID
+}
+
+// Service saves notifications in the background so the request that caused
+// one never waits on it
+type Service struct {
+	db     *database.Queries
+	pubsub pubsub.PubSub
+	queue  chan Notification
+}
+
+// NewService returns a Service that saves to db and announces each saved
+// notification on ps. Nothing is saved until Run is called.
+func NewService(db *database.Queries, ps pubsub.PubSub) *Service {
+	return &Service{
+		db:     db,
+		pubsub: ps,
+		queue:  make(chan Notification, queueSize),
+	}
+}
+
+// Notify queues n to be saved and returns straight away. Notifying users
+// about their own actions does nothing. If the queue is full n is dropped,
+// a missed notification is better than a slow request.
+func (s *Service) Notify(n Notification) {
+	if n.ActorID.Valid && n.ActorID.UUID == n.UserID {
+		return
+	}
+	select {
+	case s.queue <- n:
+	default:
+		log.Printf("Notification queue is full, dropping %s notification for %s", n.Kind, n.UserID)
+	}
+}
+
+// Run saves queued notifications until ctx is done
+func (s *Service) Run(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case n := <-s.queue:
+			err := s.save(ctx, n)
+			if err != nil {
+				log.Printf("Error saving %s notification for %s: %s", n.Kind, n.UserID, err)
+			}
+		}
+	}
+}
+
+func (s *Service) save(ctx context.Context, n Notification) error {
+	saved, err := s.db.CreateNotification(ctx, database.CreateNotificationParams{
+		UserID:  n.UserID,
+		ActorID: n.ActorID,
+		Kind:    string(n.Kind),
+		ChirpID: n.ChirpID,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		// The user blocked or muted whoever caused it
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	// Live clients learn about it from the announcement. It's saved either
+	// way, so failing to announce isn't an error for the caller.
+	payload, err := json.Marshal(Announcement{ID: saved.ID, UserID: saved.UserID})
+	if err != nil {
+		return err
+	}
+	err = s.pubsub.Publish(ctx, Channel, string(payload))
+	if err != nil {
+		log.Printf("Error announcing notification %s: %s", saved.ID, err)
+	}
+	return nil
+}
@@ -0,0 +1,56 @@
+package notifications
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestNotify(t *testing.T) {
+	userID := uuid.New()
+	otherID := uuid.New()
+
+	tests := []struct {
+		name       string
+		n          Notification
+		wantQueued bool
+	}{
+		{
+			name:       "Someone else's action",
+			n:          Notification{UserID: userID, ActorID: uuid.NullUUID{UUID: otherID, Valid: true}, Kind: KindLike},
+			wantQueued: true,
+		},
+		{
+			name:       "No actor",
+			n:          Notification{UserID: userID, Kind: KindChirpyRed},
+			wantQueued: true,
+		},
+		{
+			name:       "Your own action",
+			n:          Notification{UserID: userID, ActorID: uuid.NullUUID{UUID: userID, Valid: true}, Kind: KindReply},
+			wantQueued: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewService(nil, nil)
+			s.Notify(tt.n)
+			if got := len(s.queue) == 1; got != tt.wantQueued {
+				t.Errorf("Notify() queued = %v, want %v", got, tt.wantQueued)
+			}
+		})
+	}
+}
+
+func TestNotifyFullQueue(t *testing.T) {
+	s := NewService(nil, nil)
+	n := Notification{UserID: uuid.New(), Kind: KindChirpyRed}
+	// Nothing is draining the queue, so this would hang if Notify blocked
+	for i := 0; i < queueSize+10; i++ {
+		s.Notify(n)
+	}
+	if len(s.queue) != queueSize {
+		t.Errorf("queue has %d notifications, want %d", len(s.queue), queueSize)
+	}
+}
@@ -0,0 +1,142 @@
+package pagination
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// DefaultLimit is the page size used when the client doesn't ask for one
+	DefaultLimit = 20
+	// MaxLimit caps how many rows a single page can return
+	MaxLimit = 100
+)
+
+// Direction says which way a cursor walks from its position
+type Direction string
+
+const (
+	// DirectionNext walks away from the start of the list
+	DirectionNext Direction = "next"
+	// DirectionPrev walks back towards the start of the list
+	DirectionPrev Direction = "prev"
+)
+
+// ErrInvalidCursor -
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// ErrInvalidLimit -
+var ErrInvalidLimit = errors.New("invalid limit")
+
+// Cursor marks a position in a list ordered by (created_at, id), or by
+// (rank, created_at, id) for lists sorted by relevance.
+// Clients only ever see it as an opaque string.
+type Cursor struct {
+	Rank      *float64  `json:"r,omitempty"`
+	CreatedAt time.Time `json:"t"`
+	ID        uuid.UUID `json:"id"`
+	Direction Direction `json:"d"`
+}
+
+// Encode turns the cursor into an opaque, URL safe string
+func (c Cursor) Encode() string {
+	dat, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(dat)
+}
+
+// Decode parses a string produced by Cursor.Encode
+func Decode(s string) (Cursor, error) {
+	dat, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return Cursor{}, ErrInvalidCursor
+	}
+	c := Cursor{}
+	if err := json.Unmarshal(dat, &c); err != nil {
+		return Cursor{}, ErrInvalidCursor
+	}
+	if c.ID == uuid.Nil || c.CreatedAt.IsZero() {
+		return Cursor{}, ErrInvalidCursor
+	}
+	if c.Direction != DirectionNext && c.Direction != DirectionPrev {
+		return Cursor{}, ErrInvalidCursor
+	}
+	return c, nil
+}
+
+// ParseLimit reads a page size from a query string value.
+// An empty value gives DefaultLimit.
+func ParseLimit(s string) (int, error) {
+	if s == "" {
+		return DefaultLimit, nil
+	}
+	limit, err := strconv.Atoi(s)
+	if err != nil || limit < 1 || limit > MaxLimit {
+		return 0, ErrInvalidLimit
+	}
+	return limit, nil
+}
+
+// LinkHeader builds an RFC 8288 Link header value pointing at the next and
+// previous pages. The cursors replace any cursor already in u's query.
+// Empty cursors are left out.
+func LinkHeader(u *url.URL, nextCursor, prevCursor string) string {
+	links := []string{}
+	for _, link := range []struct {
+		rel    string
+		cursor string
+	}{
+		{rel: "next", cursor: nextCursor},
+		{rel: "prev", cursor: prevCursor},
+	} {
+		if link.cursor == "" {
+			continue
+		}
+		query := u.Query()
+		query.Set("cursor", link.cursor)
+		target := url.URL{Path: u.Path, RawQuery: query.Encode()}
+		links = append(links, "<"+target.String()+`>; rel="`+link.rel+`"`)
+	}
+	return strings.Join(links, ", ")
+}
+
+// Paginate trims rows fetched with a LIMIT of limit+1 down to one page and
+// works out the cursors on either side of it. Rows must be in the order they
+// were walked, so a page fetched with a DirectionPrev cursor is reversed back
+// into display order. key returns the position of a row; its Direction is
+// filled in here.
+func Paginate[T any](rows []T, limit int, cursor *Cursor, key func(T) Cursor) (page []T, nextCursor, prevCursor string) {
+	hasMore := len(rows) > limit
+	if hasMore {
+		rows = rows[:limit]
+	}
+
+	hasNext, hasPrev := hasMore, cursor != nil
+	if cursor != nil && cursor.Direction == DirectionPrev {
+		for i, j := 0, len(rows)-1; i < j; i, j = i+1, j-1 {
+			rows[i], rows[j] = rows[j], rows[i]
+		}
+		hasNext, hasPrev = true, hasMore
+	}
+	if len(rows) == 0 {
+		return rows, "", ""
+	}
+
+	if hasNext {
+		next := key(rows[len(rows)-1])
+		next.Direction = DirectionNext
+		nextCursor = next.Encode()
+	}
+	if hasPrev {
+		prev := key(rows[0])
+		prev.Direction = DirectionPrev
+		prevCursor = prev.Encode()
+	}
+	return rows, nextCursor, prevCursor
+}
@@ -0,0 +1,216 @@
+package pagination
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestDecode(t *testing.T) {
+	cursor := Cursor{
+		CreatedAt: time.Date(2024, 11, 1, 12, 30, 0, 123456000, time.UTC),
+		ID:        uuid.New(),
+		Direction: DirectionNext,
+	}
+	rank := 0.0607927
+	rankedCursor := cursor
+	rankedCursor.Rank = &rank
+
+	tests := []struct {
+		name    string
+		input   string
+		want    Cursor
+		wantErr bool
+	}{
+		{
+			name:    "Round trip",
+			input:   cursor.Encode(),
+			want:    cursor,
+			wantErr: false,
+		},
+		{
+			name:    "Round trip with rank",
+			input:   rankedCursor.Encode(),
+			want:    rankedCursor,
+			wantErr: false,
+		},
+		{
+			name:    "Not base64",
+			input:   "not a cursor!",
+			wantErr: true,
+		},
+		{
+			name:    "Not JSON",
+			input:   "bm90IGpzb24",
+			wantErr: true,
+		},
+		{
+			name:    "Missing direction",
+			input:   Cursor{CreatedAt: cursor.CreatedAt, ID: cursor.ID}.Encode(),
+			wantErr: true,
+		},
+		{
+			name:    "Missing ID",
+			input:   Cursor{CreatedAt: cursor.CreatedAt, Direction: DirectionPrev}.Encode(),
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Decode(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Decode() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && (!got.CreatedAt.Equal(tt.want.CreatedAt) || got.ID != tt.want.ID || got.Direction != tt.want.Direction) {
+				t.Errorf("Decode() got = %v, want %v", got, tt.want)
+			}
+			if !tt.wantErr && (got.Rank == nil) != (tt.want.Rank == nil) {
+				t.Errorf("Decode() got rank = %v, want %v", got.Rank, tt.want.Rank)
+			}
+			if !tt.wantErr && got.Rank != nil && *got.Rank != *tt.want.Rank {
+				t.Errorf("Decode() got rank = %v, want %v", *got.Rank, *tt.want.Rank)
+			}
+		})
+	}
+}
+
+func TestParseLimit(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    int
+		wantErr bool
+	}{
+		{name: "Default", input: "", want: DefaultLimit},
+		{name: "Valid", input: "5", want: 5},
+		{name: "Max", input: "100", want: MaxLimit},
+		{name: "Too big", input: "101", wantErr: true},
+		{name: "Zero", input: "0", wantErr: true},
+		{name: "Not a number", input: "ten", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseLimit(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseLimit() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("ParseLimit() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+type row struct {
+	createdAt time.Time
+	id        uuid.UUID
+}
+
+func rowKey(r row) Cursor {
+	return Cursor{CreatedAt: r.createdAt, ID: r.id}
+}
+
+func makeRows(n int) []row {
+	rows := []row{}
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i := 0; i < n; i++ {
+		rows = append(rows, row{createdAt: start.Add(time.Duration(i) * time.Minute), id: uuid.New()})
+	}
+	return rows
+}
+
+func TestPaginate(t *testing.T) {
+	rows := makeRows(4)
+	nextCursor := &Cursor{CreatedAt: rows[0].createdAt, ID: rows[0].id, Direction: DirectionNext}
+	prevCursor := &Cursor{CreatedAt: rows[3].createdAt, ID: rows[3].id, Direction: DirectionPrev}
+
+	tests := []struct {
+		name      string
+		rows      []row
+		limit     int
+		cursor    *Cursor
+		wantFirst uuid.UUID
+		wantLen   int
+		wantNext  bool
+		wantPrev  bool
+	}{
+		{
+			name:      "First page with more",
+			rows:      rows[:3],
+			limit:     2,
+			wantFirst: rows[0].id,
+			wantLen:   2,
+			wantNext:  true,
+			wantPrev:  false,
+		},
+		{
+			name:      "Last page",
+			rows:      rows[1:3],
+			limit:     2,
+			cursor:    nextCursor,
+			wantFirst: rows[1].id,
+			wantLen:   2,
+			wantNext:  false,
+			wantPrev:  true,
+		},
+		{
+			name:      "Walking back is reversed",
+			rows:      []row{rows[2], rows[1], rows[0]},
+			limit:     2,
+			cursor:    prevCursor,
+			wantFirst: rows[1].id,
+			wantLen:   2,
+			wantNext:  true,
+			wantPrev:  true,
+		},
+		{
+			name:    "Empty page",
+			rows:    []row{},
+			limit:   2,
+			cursor:  nextCursor,
+			wantLen: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			page, next, prev := Paginate(append([]row{}, tt.rows...), tt.limit, tt.cursor, rowKey)
+			if len(page) != tt.wantLen {
+				t.Fatalf("Paginate() got %d rows, want %d", len(page), tt.wantLen)
+			}
+			if tt.wantLen > 0 && page[0].id != tt.wantFirst {
+				t.Errorf("Paginate() first row = %v, want %v", page[0].id, tt.wantFirst)
+			}
+			if (next != "") != tt.wantNext {
+				t.Errorf("Paginate() next cursor = %q, want present %v", next, tt.wantNext)
+			}
+			if (prev != "") != tt.wantPrev {
+				t.Errorf("Paginate() prev cursor = %q, want present %v", prev, tt.wantPrev)
+			}
+		})
+	}
+}
+
+func TestLinkHeader(t *testing.T) {
+	u, _ := url.Parse("/api/chirps?sort=desc&cursor=old")
+	got := LinkHeader(u, "abc", "")
+	if !strings.Contains(got, "cursor=abc") || !strings.Contains(got, `rel="next"`) {
+		t.Errorf("LinkHeader() = %q, want next link with new cursor", got)
+	}
+	if !strings.Contains(got, "sort=desc") {
+		t.Errorf("LinkHeader() = %q, want other query params kept", got)
+	}
+	if strings.Contains(got, `rel="prev"`) {
+		t.Errorf("LinkHeader() = %q, want no prev link", got)
+	}
+	if got := LinkHeader(u, "", ""); got != "" {
+		t.Errorf("LinkHeader() = %q, want empty", got)
+	}
+}
@@ -0,0 +1,60 @@
+package polls
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/srinivassivaratri/Chirpy/internal/filter"
+)
+
+const (
+	// Fewest and most options a poll can have
+	MinOptions = 2
+	MaxOptions = 4
+	// Longest option, in characters
+	MaxOptionLength = 25
+	// Shortest and longest a poll can stay open
+	MinDuration = 5 * time.Minute
+	MaxDuration = 7 * 24 * time.Hour
+)
+
+// Validate checks a new poll's options and how long it stays open. Options
+// can't repeat, ignoring case and surrounding space.
+func Validate(options []string, duration time.Duration) error {
+	if len(options) < MinOptions || len(options) > MaxOptions {
+		return fmt.Errorf("A poll needs %d to %d options", MinOptions, MaxOptions)
+	}
+	seen := map[string]bool{}
+	for _, option := range options {
+		trimmed := strings.TrimSpace(option)
+		if trimmed == "" {
+			return errors.New("Poll options can't be empty")
+		}
+		if filter.Length(trimmed) > MaxOptionLength {
+			return fmt.Errorf("Poll options can be at most %d characters", MaxOptionLength)
+		}
+		key := strings.ToLower(trimmed)
+		if seen[key] {
+			return errors.New("Poll options must be different")
+		}
+		seen[key] = true
+	}
+	if duration < MinDuration || duration > MaxDuration {
+		return errors.New("A poll must stay open between 5 minutes and 7 days")
+	}
+	return nil
+}
+
+// Closed reports whether a poll closing at closesAt has closed by now
+func Closed(closesAt, now time.Time) bool {
+	return !now.Before(closesAt)
+}
+
+// ResultsVisible reports whether someone can see how a poll's votes are
+// split. Results stay hidden until they vote or the poll closes, so early
+// results don't sway anyone.
+func ResultsVisible(voted bool, closesAt, now time.Time) bool {
+	return voted || Closed(closesAt, now)
+}
@@ -0,0 +1,63 @@
+package polls
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name     string
+		options  []string
+		duration time.Duration
+		wantErr  bool
+	}{
+		{name: "Two options", options: []string{"Yes", "No"}, duration: time.Hour},
+		{name: "Four options", options: []string{"A", "B", "C", "D"}, duration: time.Hour},
+		{name: "One option", options: []string{"Yes"}, duration: time.Hour, wantErr: true},
+		{name: "Five options", options: []string{"A", "B", "C", "D", "E"}, duration: time.Hour, wantErr: true},
+		{name: "No options", options: nil, duration: time.Hour, wantErr: true},
+		{name: "Blank option", options: []string{"Yes", "  "}, duration: time.Hour, wantErr: true},
+		{name: "Repeated option", options: []string{"Yes", " yes"}, duration: time.Hour, wantErr: true},
+		{name: "Longest option", options: []string{strings.Repeat("a", MaxOptionLength), "b"}, duration: time.Hour},
+		{name: "Option too long", options: []string{strings.Repeat("a", MaxOptionLength+1), "b"}, duration: time.Hour, wantErr: true},
+		{name: "Emoji count once", options: []string{strings.Repeat("\U0001F44D", MaxOptionLength), "b"}, duration: time.Hour},
+		{name: "Shortest duration", options: []string{"Yes", "No"}, duration: MinDuration},
+		{name: "Longest duration", options: []string{"Yes", "No"}, duration: MaxDuration},
+		{name: "Too short", options: []string{"Yes", "No"}, duration: MinDuration - time.Second, wantErr: true},
+		{name: "Too long", options: []string{"Yes", "No"}, duration: MaxDuration + time.Second, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Validate(tt.options, tt.duration)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestResultsVisible(t *testing.T) {
+	closesAt := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name  string
+		voted bool
+		now   time.Time
+		want  bool
+	}{
+		{name: "Open, not voted", voted: false, now: closesAt.Add(-time.Minute), want: false},
+		{name: "Open, voted", voted: true, now: closesAt.Add(-time.Minute), want: true},
+		{name: "Closing now", voted: false, now: closesAt, want: true},
+		{name: "Closed, not voted", voted: false, now: closesAt.Add(time.Minute), want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ResultsVisible(tt.voted, closesAt, tt.now); got != tt.want {
+				t.Errorf("ResultsVisible(%v, %v, %v) = %v, want %v", tt.voted, closesAt, tt.now, got, tt.want)
+			}
+		})
+	}
+}
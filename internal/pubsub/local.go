@@ -0,0 +1,28 @@
+package pubsub
+
+import "context"
+
+// Local is a PubSub that only reaches subscribers in the same process. It's
+// meant for tests and for running a single instance without Postgres.
+type Local struct {
+	broker *broker
+}
+
+// NewLocal returns a Local with no subscribers
+func NewLocal() *Local {
+	return &Local{
+		broker: newBroker(),
+	}
+}
+
+// Publish delivers payload to the channel's subscribers before returning
+func (l *Local) Publish(ctx context.Context, channel, payload string) error {
+	l.broker.deliver(Message{Channel: channel, Payload: payload})
+	return nil
+}
+
+// Subscribe starts receiving messages published on channel
+func (l *Local) Subscribe(channel string) (*Subscription, error) {
+	s, _ := l.broker.subscribe(channel, nil)
+	return s, nil
+}
@@ -0,0 +1,98 @@
+package pubsub
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/srinivassivaratri/Chirpy/internal/database"
+)
+
+const (
+	// Backoff between attempts to reconnect the listening connection
+	minReconnectInterval = time.Second
+	maxReconnectInterval = time.Minute
+	// How often the listening connection is checked while it's quiet
+	pingInterval = 90 * time.Second
+)
+
+// Postgres is a PubSub built on LISTEN/NOTIFY, so messages reach every
+// instance connected to the same database. Payloads are limited to just
+// under 8000 bytes; send IDs and let subscribers load the rest.
+type Postgres struct {
+	db       *database.Queries
+	listener *pq.Listener
+	broker   *broker
+}
+
+// NewPostgres publishes through db and listens on its own connection to
+// dbURL, reconnecting with backoff if it drops. Nothing is received until
+// Run is called.
+func NewPostgres(db *database.Queries, dbURL string) *Postgres {
+	return &Postgres{
+		db: db,
+		listener: pq.NewListener(dbURL, minReconnectInterval, maxReconnectInterval, func(event pq.ListenerEventType, err error) {
+			if err != nil {
+				log.Printf("Postgres listener error: %s", err)
+			}
+		}),
+		broker: newBroker(),
+	}
+}
+
+// Publish sends payload to every subscriber of channel on every instance
+func (p *Postgres) Publish(ctx context.Context, channel, payload string) error {
+	return p.db.Notify(ctx, database.NotifyParams{
+		Channel: channel,
+		Payload: payload,
+	})
+}
+
+// Subscribe starts receiving messages published on channel. The first
+// subscriber to a channel waits until the listening connection is up.
+func (p *Postgres) Subscribe(channel string) (*Subscription, error) {
+	s, first := p.broker.subscribe(channel, func() {
+		err := p.listener.Unlisten(channel)
+		if err != nil && !errors.Is(err, pq.ErrChannelNotOpen) {
+			log.Printf("Error unlistening from %s: %s", channel, err)
+		}
+	})
+	if first {
+		err := p.listener.Listen(channel)
+		if err != nil && !errors.Is(err, pq.ErrChannelAlreadyOpen) {
+			s.Close()
+			return nil, err
+		}
+	}
+	return s, nil
+}
+
+// Run hands notifications to subscribers until ctx is done, then closes the
+// listening connection
+func (p *Postgres) Run(ctx context.Context) {
+	defer p.listener.Close()
+	ticker := time.NewTicker(pingInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case n := <-p.listener.Notify:
+			// pq sends nil after reconnecting, anything sent while the
+			// connection was down is lost
+			if n == nil {
+				p.broker.resync()
+				continue
+			}
+			p.broker.deliver(Message{Channel: n.Channel, Payload: n.Extra})
+		case <-ticker.C:
+			// Finds a dead connection sooner than waiting for TCP to notice
+			err := p.listener.Ping()
+			if err != nil {
+				log.Printf("Postgres listener ping failed: %s", err)
+			}
+		}
+	}
+}
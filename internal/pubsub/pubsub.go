@@ -0,0 +1,112 @@
+package pubsub
+
+import (
+	"context"
+	"log"
+	"sync"
+)
+
+// How many messages a subscriber can fall behind before new ones are dropped
+const bufferSize = 64
+
+// Message is a payload published on a channel
+type Message struct {
+	Channel string
+	Payload string
+	// Resync is set, with no payload, when messages on Channel may have been
+	// lost, for example while the connection to Postgres was down.
+	// Subscribers should catch up from wherever the data itself lives.
+	Resync bool
+}
+
+// PubSub delivers messages published on a channel to every subscriber of
+// that channel
+type PubSub interface {
+	Publish(ctx context.Context, channel, payload string) error
+	Subscribe(channel string) (*Subscription, error)
+}
+
+// Subscription receives messages published on one channel until it's closed
+type Subscription struct {
+	messages    chan Message
+	unsubscribe func()
+	closeOnce   sync.Once
+}
+
+// Messages delivers messages in the order they were received
+func (s *Subscription) Messages() <-chan Message {
+	return s.messages
+}
+
+// Close stops delivery
+func (s *Subscription) Close() {
+	s.closeOnce.Do(s.unsubscribe)
+}
+
+// broker hands messages to the local subscribers of each channel
+type broker struct {
+	mu          sync.Mutex
+	subscribers map[string]map[*Subscription]struct{}
+}
+
+func newBroker() *broker {
+	return &broker{
+		subscribers: map[string]map[*Subscription]struct{}{},
+	}
+}
+
+// subscribe adds a subscriber to channel and reports whether it's the first
+func (b *broker) subscribe(channel string, onLast func()) (*Subscription, bool) {
+	s := &Subscription{
+		messages: make(chan Message, bufferSize),
+	}
+	s.unsubscribe = func() {
+		b.mu.Lock()
+		delete(b.subscribers[channel], s)
+		last := len(b.subscribers[channel]) == 0
+		if last {
+			delete(b.subscribers, channel)
+		}
+		b.mu.Unlock()
+		if last && onLast != nil {
+			onLast()
+		}
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	first := len(b.subscribers[channel]) == 0
+	if first {
+		b.subscribers[channel] = map[*Subscription]struct{}{}
+	}
+	b.subscribers[channel][s] = struct{}{}
+	return s, first
+}
+
+// deliver hands m to every subscriber of its channel without waiting on any
+// of them. A subscriber that's too far behind misses it.
+func (b *broker) deliver(m Message) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for s := range b.subscribers[m.Channel] {
+		select {
+		case s.messages <- m:
+		default:
+			log.Printf("Subscriber to %s is full, dropping message", m.Channel)
+		}
+	}
+}
+
+// resync tells every subscriber of every channel that it may have missed
+// messages
+func (b *broker) resync() {
+	b.mu.Lock()
+	channels := []string{}
+	for channel := range b.subscribers {
+		channels = append(channels, channel)
+	}
+	b.mu.Unlock()
+	for _, channel := range channels {
+		b.deliver(Message{Channel: channel, Resync: true})
+	}
+}
@@ -0,0 +1,93 @@
+package pubsub
+
+import (
+	"context"
+	"testing"
+)
+
+func TestLocal(t *testing.T) {
+	ps := NewLocal()
+	chirps, err := ps.Subscribe("chirps")
+	if err != nil {
+		t.Fatalf("Subscribe() error = %v", err)
+	}
+	defer chirps.Close()
+	likes, err := ps.Subscribe("likes")
+	if err != nil {
+		t.Fatalf("Subscribe() error = %v", err)
+	}
+	defer likes.Close()
+
+	err = ps.Publish(context.Background(), "chirps", "hello")
+	if err != nil {
+		t.Fatalf("Publish() error = %v", err)
+	}
+
+	if got := len(likes.Messages()); got != 0 {
+		t.Errorf("other channel got %d messages, want 0", got)
+	}
+	select {
+	case m := <-chirps.Messages():
+		if m.Channel != "chirps" || m.Payload != "hello" {
+			t.Errorf("got %+v, want hello on chirps", m)
+		}
+	default:
+		t.Fatal("subscriber got no message")
+	}
+}
+
+func TestSubscriptionClose(t *testing.T) {
+	ps := NewLocal()
+	s, _ := ps.Subscribe("chirps")
+	s.Close()
+	s.Close()
+	ps.Publish(context.Background(), "chirps", "hello")
+	if got := len(s.Messages()); got != 0 {
+		t.Errorf("closed subscriber got %d messages, want 0", got)
+	}
+}
+
+func TestBroker(t *testing.T) {
+	b := newBroker()
+	unlistened := false
+	first, isFirst := b.subscribe("chirps", func() { unlistened = true })
+	if !isFirst {
+		t.Error("first subscriber wasn't reported as first")
+	}
+	second, isFirst := b.subscribe("chirps", func() { unlistened = true })
+	if isFirst {
+		t.Error("second subscriber was reported as first")
+	}
+
+	// A full subscriber misses messages rather than blocking delivery
+	for i := 0; i <= bufferSize; i++ {
+		b.deliver(Message{Channel: "chirps", Payload: "hello"})
+	}
+	if got := len(first.Messages()); got != bufferSize {
+		t.Errorf("subscriber has %d messages, want %d", got, bufferSize)
+	}
+
+	first.Close()
+	if unlistened {
+		t.Error("channel was unlistened while it still had a subscriber")
+	}
+	second.Close()
+	if !unlistened {
+		t.Error("channel wasn't unlistened after its last subscriber left")
+	}
+}
+
+func TestBrokerResync(t *testing.T) {
+	b := newBroker()
+	s, _ := b.subscribe("chirps", nil)
+	defer s.Close()
+	b.resync()
+	select {
+	case m := <-s.Messages():
+		if !m.Resync || m.Channel != "chirps" {
+			t.Errorf("got %+v, want a resync on chirps", m)
+		}
+	default:
+		t.Fatal("subscriber got no resync")
+	}
+}
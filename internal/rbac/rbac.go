@@ -0,0 +1,50 @@
+package rbac
+
+// Role is what a user is allowed to do. Every user has exactly one.
+type Role string
+
+const (
+	RoleUser      Role = "user"
+	RoleModerator Role = "moderator"
+	RoleAdmin     Role = "admin"
+)
+
+// Permission is something a route can require
+type Permission string
+
+const (
+	// PermModerate lets a user work the report queue and see hidden chirps
+	PermModerate Permission = "moderate"
+	// PermViewMetrics lets a user see the admin metrics page
+	PermViewMetrics Permission = "view_metrics"
+	// PermManageRoles lets a user grant and revoke roles
+	PermManageRoles Permission = "manage_roles"
+	// PermManageFilters lets a user edit and reload the content filters
+	PermManageFilters Permission = "manage_filters"
+	// PermReset lets a user wipe the database on a dev server
+	PermReset Permission = "reset"
+	// PermDenyTokens lets a user turn down access tokens before they expire
+	PermDenyTokens Permission = "deny_tokens"
+)
+
+var permissions = map[Role][]Permission{
+	RoleUser:      {},
+	RoleModerator: {PermModerate},
+	RoleAdmin:     {PermModerate, PermViewMetrics, PermManageRoles, PermManageFilters, PermReset, PermDenyTokens},
+}
+
+// ValidRole reports whether s is a role users can have
+func ValidRole(s string) bool {
+	_, ok := permissions[Role(s)]
+	return ok
+}
+
+// Can reports whether the role has the permission. Unknown roles have none.
+func (r Role) Can(p Permission) bool {
+	for _, perm := range permissions[r] {
+		if perm == p {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,60 @@
+package rbac
+
+import "testing"
+
+func TestValidRole(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  bool
+	}{
+		{name: "User", input: "user", want: true},
+		{name: "Moderator", input: "moderator", want: true},
+		{name: "Admin", input: "admin", want: true},
+		{name: "Wrong case", input: "Admin", want: false},
+		{name: "Unknown", input: "owner", want: false},
+		{name: "Empty", input: "", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ValidRole(tt.input); got != tt.want {
+				t.Errorf("ValidRole(%q) = %v, want %v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCan(t *testing.T) {
+	tests := []struct {
+		name string
+		role Role
+		perm Permission
+		want bool
+	}{
+		{name: "User can't moderate", role: RoleUser, perm: PermModerate, want: false},
+		{name: "User can't view metrics", role: RoleUser, perm: PermViewMetrics, want: false},
+		{name: "Moderator can moderate", role: RoleModerator, perm: PermModerate, want: true},
+		{name: "Moderator can't view metrics", role: RoleModerator, perm: PermViewMetrics, want: false},
+		{name: "Moderator can't manage roles", role: RoleModerator, perm: PermManageRoles, want: false},
+		{name: "Admin can moderate", role: RoleAdmin, perm: PermModerate, want: true},
+		{name: "Admin can view metrics", role: RoleAdmin, perm: PermViewMetrics, want: true},
+		{name: "Admin can manage roles", role: RoleAdmin, perm: PermManageRoles, want: true},
+		{name: "Moderator can't manage filters", role: RoleModerator, perm: PermManageFilters, want: false},
+		{name: "Admin can manage filters", role: RoleAdmin, perm: PermManageFilters, want: true},
+		{name: "User can't reset", role: RoleUser, perm: PermReset, want: false},
+		{name: "Moderator can't reset", role: RoleModerator, perm: PermReset, want: false},
+		{name: "Admin can reset", role: RoleAdmin, perm: PermReset, want: true},
+		{name: "Moderator can't deny tokens", role: RoleModerator, perm: PermDenyTokens, want: false},
+		{name: "Admin can deny tokens", role: RoleAdmin, perm: PermDenyTokens, want: true},
+		{name: "Unknown role", role: Role("owner"), perm: PermModerate, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.role.Can(tt.perm); got != tt.want {
+				t.Errorf("%q.Can(%q) = %v, want %v", tt.role, tt.perm, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,34 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"strings"
+)
+
+// ErrInvalidKey is returned for keys that could escape where objects are kept
+var ErrInvalidKey = errors.New("invalid storage key")
+
+// Storage keeps uploaded files somewhere clients can fetch them from
+type Storage interface {
+	// Put saves data under key, replacing anything already there
+	Put(ctx context.Context, key, contentType string, data []byte) error
+	// Delete removes key. Deleting a key that isn't there isn't an error.
+	Delete(ctx context.Context, key string) error
+	// URL is where clients can fetch key from
+	URL(key string) string
+}
+
+// validKey reports whether key is a relative, slash separated path without
+// any . or .. segments
+func validKey(key string) bool {
+	if key == "" || strings.HasPrefix(key, "/") {
+		return false
+	}
+	for _, segment := range strings.Split(key, "/") {
+		if segment == "" || segment == "." || segment == ".." || strings.Contains(segment, "\\") {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,133 @@
+package stream
+
+import (
+	"slices"
+	"sync"
+
+	"github.com/google/uuid"
+)
+
+// How many events a subscriber can fall behind before it's dropped
+const bufferSize = 64
+
+// Event is something that happened to a chirp, ready to send to clients
+type Event struct {
+	// ID orders events and lets clients resume after a reconnect
+	ID      int64
+	Kind    string
+	ChirpID uuid.UUID
+	// UserID is the chirp's author
+	UserID   uuid.UUID
+	ThreadID uuid.UUID
+	// Tags are the chirp's hashtags
+	Tags []string
+	// Data is the JSON sent to clients
+	Data []byte
+}
+
+// Filter picks the events a subscriber wants. Empty fields match everything.
+type Filter struct {
+	AuthorID uuid.NullUUID
+	ThreadID uuid.NullUUID
+	Hashtag  string
+	// Authors limits events to chirps by these users when it isn't nil
+	Authors map[uuid.UUID]bool
+	// Hidden drops chirps by these users, like the ones a viewer blocked
+	Hidden map[uuid.UUID]bool
+}
+
+// Match reports whether e passes every part of the filter
+func (f Filter) Match(e Event) bool {
+	if f.AuthorID.Valid && e.UserID != f.AuthorID.UUID {
+		return false
+	}
+	if f.ThreadID.Valid && e.ThreadID != f.ThreadID.UUID {
+		return false
+	}
+	if f.Hashtag != "" && !slices.Contains(e.Tags, f.Hashtag) {
+		return false
+	}
+	if f.Authors != nil && !f.Authors[e.UserID] {
+		return false
+	}
+	if f.Hidden[e.UserID] {
+		return false
+	}
+	return true
+}
+
+// Hub fans events out to every subscriber whose filter matches them
+type Hub struct {
+	mu          sync.Mutex
+	subscribers map[*Subscription]struct{}
+}
+
+// NewHub returns a Hub with no subscribers
+func NewHub() *Hub {
+	return &Hub{
+		subscribers: map[*Subscription]struct{}{},
+	}
+}
+
+// Subscription receives the events matching its filter until it's closed or
+// dropped
+type Subscription struct {
+	hub     *Hub
+	filter  Filter
+	events  chan Event
+	dropped chan struct{}
+}
+
+// Subscribe starts delivering events that match f
+func (h *Hub) Subscribe(f Filter) *Subscription {
+	s := &Subscription{
+		hub:     h,
+		filter:  f,
+		events:  make(chan Event, bufferSize),
+		dropped: make(chan struct{}),
+	}
+	h.mu.Lock()
+	h.subscribers[s] = struct{}{}
+	h.mu.Unlock()
+	return s
+}
+
+// Publish sends events to every matching subscriber without waiting on any
+// of them. A subscriber whose buffer is full is dropped rather than slowing
+// everyone else down; it can catch up by resuming from the last event it saw.
+func (h *Hub) Publish(events ...Event) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for s := range h.subscribers {
+	events:
+		for _, e := range events {
+			if !s.filter.Match(e) {
+				continue
+			}
+			select {
+			case s.events <- e:
+			default:
+				delete(h.subscribers, s)
+				close(s.dropped)
+				break events
+			}
+		}
+	}
+}
+
+// Events delivers matching events in the order they were published
+func (s *Subscription) Events() <-chan Event {
+	return s.events
+}
+
+// Dropped is closed if the subscriber fell too far behind and was dropped
+func (s *Subscription) Dropped() <-chan struct{} {
+	return s.dropped
+}
+
+// Close stops delivery. It's safe to call after the subscription was dropped.
+func (s *Subscription) Close() {
+	s.hub.mu.Lock()
+	delete(s.hub.subscribers, s)
+	s.hub.mu.Unlock()
+}
@@ -0,0 +1,100 @@
+package stream
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestFilterMatch(t *testing.T) {
+	authorID := uuid.New()
+	otherID := uuid.New()
+	threadID := uuid.New()
+	event := Event{ID: 1, Kind: "chirp_created", UserID: authorID, ThreadID: threadID, Tags: []string{"golang", "chirpy"}}
+
+	tests := []struct {
+		name   string
+		filter Filter
+		want   bool
+	}{
+		{name: "Empty filter", filter: Filter{}, want: true},
+		{name: "Matching author", filter: Filter{AuthorID: uuid.NullUUID{UUID: authorID, Valid: true}}, want: true},
+		{name: "Other author", filter: Filter{AuthorID: uuid.NullUUID{UUID: otherID, Valid: true}}, want: false},
+		{name: "Matching thread", filter: Filter{ThreadID: uuid.NullUUID{UUID: threadID, Valid: true}}, want: true},
+		{name: "Other thread", filter: Filter{ThreadID: uuid.NullUUID{UUID: otherID, Valid: true}}, want: false},
+		{name: "Matching hashtag", filter: Filter{Hashtag: "chirpy"}, want: true},
+		{name: "Other hashtag", filter: Filter{Hashtag: "rust"}, want: false},
+		{name: "Followed author", filter: Filter{Authors: map[uuid.UUID]bool{authorID: true}}, want: true},
+		{name: "Follows nobody", filter: Filter{Authors: map[uuid.UUID]bool{}}, want: false},
+		{name: "Hidden author", filter: Filter{Hidden: map[uuid.UUID]bool{authorID: true}}, want: false},
+		{name: "Someone else hidden", filter: Filter{Hidden: map[uuid.UUID]bool{otherID: true}}, want: true},
+		{
+			name:   "Every part has to match",
+			filter: Filter{AuthorID: uuid.NullUUID{UUID: authorID, Valid: true}, Hashtag: "rust"},
+			want:   false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.filter.Match(event); got != tt.want {
+				t.Errorf("Match() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHubPublish(t *testing.T) {
+	hub := NewHub()
+	authorID := uuid.New()
+	all := hub.Subscribe(Filter{})
+	defer all.Close()
+	byAuthor := hub.Subscribe(Filter{AuthorID: uuid.NullUUID{UUID: uuid.New(), Valid: true}})
+	defer byAuthor.Close()
+
+	hub.Publish(Event{ID: 1, UserID: authorID}, Event{ID: 2, UserID: authorID})
+
+	if got := len(all.Events()); got != 2 {
+		t.Errorf("unfiltered subscriber got %d events, want 2", got)
+	}
+	if got := len(byAuthor.Events()); got != 0 {
+		t.Errorf("filtered subscriber got %d events, want 0", got)
+	}
+	if e := <-all.Events(); e.ID != 1 {
+		t.Errorf("first event ID = %d, want 1", e.ID)
+	}
+}
+
+func TestHubDropsSlowSubscriber(t *testing.T) {
+	hub := NewHub()
+	slow := hub.Subscribe(Filter{})
+	defer slow.Close()
+
+	// Nobody reads from slow, so publishing one more than fits drops it
+	for i := 0; i <= bufferSize; i++ {
+		hub.Publish(Event{ID: int64(i)})
+	}
+
+	select {
+	case <-slow.Dropped():
+	default:
+		t.Fatal("slow subscriber wasn't dropped")
+	}
+
+	fresh := hub.Subscribe(Filter{})
+	defer fresh.Close()
+	hub.Publish(Event{ID: 100})
+	if got := len(fresh.Events()); got != 1 {
+		t.Errorf("subscriber after drop got %d events, want 1", got)
+	}
+}
+
+func TestSubscriptionClose(t *testing.T) {
+	hub := NewHub()
+	s := hub.Subscribe(Filter{})
+	s.Close()
+	hub.Publish(Event{ID: 1})
+	if got := len(s.Events()); got != 0 {
+		t.Errorf("closed subscriber got %d events, want 0", got)
+	}
+}
@@ -0,0 +1,147 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/srinivassivaratri/Chirpy/internal/auth"
+)
+
+const (
+	// How long a new key is published before it signs anything, unless
+	// JWT_KEY_PUBLISH_DELAY says otherwise
+	defaultKeyPublishDelay = time.Hour
+	// How often the key directory is checked for new or retired keys
+	jwtKeysReloadInterval = time.Minute
+	// How long other services can cache the JWKS
+	jwksMaxAge = 5 * time.Minute
+)
+
+// jwtKeySource is where the keys that sign and verify access JWTs come from
+type jwtKeySource struct {
+	// Directory of <kid>.pem private keys, optional
+	dir string
+	// HS256 secret. It signs when there's no key directory and otherwise
+	// only verifies tokens issued before the switch.
+	secret string
+	// Key to sign with instead of the one picked by publish time, optional
+	signingKeyID string
+	publishDelay time.Duration
+}
+
+func newJWTKeySource() (jwtKeySource, error) {
+	source := jwtKeySource{
+		dir:          os.Getenv("JWT_KEYS_DIR"),
+		secret:       os.Getenv("JWT_SECRET"),
+		signingKeyID: os.Getenv("JWT_SIGNING_KEY_ID"),
+		publishDelay: defaultKeyPublishDelay,
+	}
+	if source.dir == "" && source.secret == "" {
+		return jwtKeySource{}, errors.New("JWT_KEYS_DIR or JWT_SECRET must be set")
+	}
+	if delay := os.Getenv("JWT_KEY_PUBLISH_DELAY"); delay != "" {
+		d, err := time.ParseDuration(delay)
+		if err != nil || d < 0 {
+			return jwtKeySource{}, fmt.Errorf("invalid JWT_KEY_PUBLISH_DELAY %q", delay)
+		}
+		source.publishDelay = d
+	}
+	return source, nil
+}
+
+// load reads the keys and picks the one that signs
+func (s jwtKeySource) load() (*auth.Key, []*auth.Key, error) {
+	var secretKey *auth.Key
+	if s.secret != "" {
+		secretKey = auth.NewSecretKey("", s.secret)
+	}
+	if s.dir == "" {
+		return secretKey, nil, nil
+	}
+
+	keys, err := auth.LoadKeyDir(s.dir)
+	if err != nil {
+		return nil, nil, err
+	}
+	signing := auth.ChooseSigningKey(keys, s.publishDelay, time.Now())
+	if s.signingKeyID != "" {
+		signing = nil
+		for _, key := range keys {
+			if key.ID == s.signingKeyID {
+				signing = key
+			}
+		}
+		if signing == nil {
+			return nil, nil, fmt.Errorf("JWT_SIGNING_KEY_ID %q isn't in %s", s.signingKeyID, s.dir)
+		}
+	}
+
+	others := []*auth.Key{}
+	for _, key := range keys {
+		if key != signing {
+			others = append(others, key)
+		}
+	}
+	if secretKey != nil {
+		others = append(others, secretKey)
+	}
+	return signing, others, nil
+}
+
+func (cfg *apiConfig) reloadJWTKeys() error {
+	signing, others, err := cfg.jwtKeySource.load()
+	if err != nil {
+		return err
+	}
+	previous := cfg.jwtConfig.Keys.SigningKey()
+	err = cfg.jwtConfig.Keys.Replace(signing, others...)
+	if err != nil {
+		return err
+	}
+	if previous.ID != signing.ID {
+		log.Printf("Signing JWTs with key %q", signing.ID)
+	}
+	return nil
+}
+
+// reloadJWTKeysLoop picks up keys added to or removed from the key
+// directory, and starts signing with a new key once it has been published
+// long enough. SIGHUP reloads straight away.
+func (cfg *apiConfig) reloadJWTKeysLoop(ctx context.Context) {
+	if cfg.jwtKeySource.dir == "" {
+		return
+	}
+	hangups := make(chan os.Signal, 1)
+	signal.Notify(hangups, syscall.SIGHUP)
+	defer signal.Stop(hangups)
+	ticker := time.NewTicker(jwtKeysReloadInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-hangups:
+		case <-ticker.C:
+		}
+
+		err := cfg.reloadJWTKeys()
+		if err != nil {
+			log.Printf("Error reloading JWT keys: %s", err)
+		}
+	}
+}
+
+// handlerJWKS publishes the public keys access JWTs are signed with, so
+// other services can verify them without a shared secret
+func (cfg *apiConfig) handlerJWKS(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Cache-Control", fmt.Sprintf("public, max-age=%d", int(jwksMaxAge.Seconds())))
+	respondWithJSON(w, http.StatusOK, cfg.jwtConfig.Keys.JWKS())
+}
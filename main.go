@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"database/sql"
 	"log"
 	"net/http"
@@ -9,15 +10,32 @@ import (
 
 	"github.com/joho/godotenv"
 	_ "github.com/lib/pq"
+	"github.com/srinivassivaratri/Chirpy/internal/auth"
 	"github.com/srinivassivaratri/Chirpy/internal/database"
+	"github.com/srinivassivaratri/Chirpy/internal/filter"
+	"github.com/srinivassivaratri/Chirpy/internal/notifications"
+	"github.com/srinivassivaratri/Chirpy/internal/pubsub"
+	"github.com/srinivassivaratri/Chirpy/internal/rbac"
+	"github.com/srinivassivaratri/Chirpy/internal/storage"
+	"github.com/srinivassivaratri/Chirpy/internal/stream"
 )
 
 type apiConfig struct {
 	fileserverHits atomic.Int32
 	db             *database.Queries
+	dbConn         *sql.DB // Needed to start transactions, queries go through db
+	notifier       *notifications.Service
+	chirpStream    *stream.Hub
+	pubsub         pubsub.PubSub // Reaches every Chirpy instance, not just this one
 	platform       string
-	jwtSecret      string
+	jwtConfig      auth.JWTConfig // Signs access JWTs and says what verifies
+	jwtKeySource   jwtKeySource
+	passwords      *auth.PasswordHasher
 	polkaKey       string // Stores a secret password that Polka (payment service) uses to prove it's really them when sending us messages - like a special handshake only we and Polka know
+	adminEmail     string // If this user exists at startup they become the first admin, optional
+	filtersFile    string // Word lists for the content filter, optional
+	contentFilter  *filter.Pipeline
+	mediaStorage   storage.Storage // Where uploaded images are kept
 }
 
 func main() {
@@ -33,9 +51,18 @@ func main() {
 	if platform == "" {
 		log.Fatal("PLATFORM must be set")
 	}
-	jwtSecret := os.Getenv("JWT_SECRET")
-	if jwtSecret == "" {
-		log.Fatal("JWT_SECRET environment variable is not set")
+	// Keys from JWT_KEYS_DIR, or the JWT_SECRET shared secret
+	keySource, err := newJWTKeySource()
+	if err != nil {
+		log.Fatal(err)
+	}
+	signingKey, verifyKeys, err := keySource.load()
+	if err != nil {
+		log.Fatalf("Error loading JWT keys: %s", err)
+	}
+	jwtKeys, err := auth.NewKeyring(signingKey, verifyKeys...)
+	if err != nil {
+		log.Fatalf("Error loading JWT keys: %s", err)
 	}
 	// Get the secret key for Polka from our computer's environment settings
 	// Think of this like looking up a secret password in a safe place
@@ -49,42 +76,172 @@ func main() {
 		log.Fatal("POLKA_KEY environment variable is not set")
 	}
 
+	// Optional. argon2id cost for new password hashes, like "m=65536,t=3,p=4"
+	passwordParams := auth.DefaultPasswordParams
+	if s := os.Getenv("PASSWORD_HASH_PARAMS"); s != "" {
+		passwordParams, err = auth.ParsePasswordParams(s)
+		if err != nil {
+			log.Fatalf("Invalid PASSWORD_HASH_PARAMS: %s", err)
+		}
+	}
+	passwords, err := auth.NewPasswordHasher(passwordParams)
+	if err != nil {
+		log.Fatalf("Invalid PASSWORD_HASH_PARAMS: %s", err)
+	}
+
+	// Optional. Whoever has this email is made an admin if there isn't one yet.
+	adminEmail := os.Getenv("ADMIN_EMAIL")
+	// Optional. Without it chirps are filtered with the built in word list.
+	filtersFile := os.Getenv("FILTERS_FILE")
+
+	// Local disk unless MEDIA_STORAGE says otherwise
+	mediaStorage, err := newMediaStorage()
+	if err != nil {
+		log.Fatalf("Error setting up media storage: %s", err)
+	}
+
 	dbConn, err := sql.Open("postgres", dbURL)
 	if err != nil {
 		log.Fatalf("Error opening database: %s", err)
 	}
 	dbQueries := database.New(dbConn)
+	// JWT_ISSUER, JWT_AUDIENCE and JWT_LEEWAY, all optional
+	jwtConfig, err := newJWTConfig(jwtKeys, tokenDenylist{db: dbQueries})
+	if err != nil {
+		log.Fatal(err)
+	}
+	ps := pubsub.NewPostgres(dbQueries, dbURL)
 
 	apiCfg := apiConfig{
 		fileserverHits: atomic.Int32{},
 		db:             dbQueries,
+		dbConn:         dbConn,
+		notifier:       notifications.NewService(dbQueries, ps),
+		chirpStream:    stream.NewHub(),
+		pubsub:         ps,
 		platform:       platform,
-		jwtSecret:      jwtSecret, // Stores a secret password used to create and verify login tokens - like a special stamp that proves a document is official
-		polkaKey:       polkaKey,  // Stores a secret key shared with our payment provider Polka - like a password they use to prove it's really them sending us messages
+		jwtConfig:      jwtConfig,
+		jwtKeySource:   keySource,
+		passwords:      passwords,
+		polkaKey:       polkaKey, // Stores a secret key shared with our payment provider Polka - like a password they use to prove it's really them sending us messages
+		adminEmail:     adminEmail,
+		filtersFile:    filtersFile,
+		contentFilter:  filter.NewPipeline(),
+		mediaStorage:   mediaStorage,
+	}
+
+	err = apiCfg.reloadFilters(context.Background())
+	if err != nil {
+		log.Fatalf("Error loading content filters: %s", err)
+	}
+
+	promoted, err := apiCfg.bootstrapAdmin(context.Background())
+	if err != nil {
+		log.Fatalf("Error bootstrapping admin: %s", err)
+	}
+	if promoted {
+		log.Printf("Made %s an admin", adminEmail)
 	}
 
+	go ps.Run(context.Background())
+	go apiCfg.refreshTrendingLoop(context.Background())
+	go apiCfg.notifier.Run(context.Background())
+	go apiCfg.pollChirpEventsLoop(context.Background())
+	go apiCfg.reloadFiltersLoop(context.Background())
+	go apiCfg.cleanupMediaLoop(context.Background())
+	go apiCfg.closePollsLoop(context.Background())
+	go apiCfg.reloadJWTKeysLoop(context.Background())
+	go apiCfg.cleanupDeniedTokensLoop(context.Background())
+
 	mux := http.NewServeMux()
 	fsHandler := apiCfg.middlewareMetricsInc(http.StripPrefix("/app", http.FileServer(http.Dir(filepathRoot))))
 	mux.Handle("/app/", fsHandler)
 
 	mux.HandleFunc("GET /api/healthz", handlerReadiness)
+	mux.HandleFunc("GET /.well-known/jwks.json", apiCfg.handlerJWKS)
+
+	if local, ok := mediaStorage.(*storage.Local); ok {
+		mux.HandleFunc("GET /media/{key}", handlerMediaFile(local.Dir()))
+	}
 
 	mux.HandleFunc("POST /api/polka/webhooks", apiCfg.handlerWebhook)
 
 	mux.HandleFunc("POST /api/login", apiCfg.handlerLogin)
 	mux.HandleFunc("POST /api/refresh", apiCfg.handlerRefresh)
 	mux.HandleFunc("POST /api/revoke", apiCfg.handlerRevoke)
+	mux.HandleFunc("GET /api/sessions", apiCfg.handlerSessionsGet)
+	mux.HandleFunc("DELETE /api/sessions", apiCfg.handlerSessionsDeleteOthers)
+	mux.HandleFunc("DELETE /api/sessions/{sessionID}", apiCfg.handlerSessionsDelete)
 
 	mux.HandleFunc("POST /api/users", apiCfg.handlerUsersCreate)
 	mux.HandleFunc("PUT /api/users", apiCfg.handlerUsersUpdate)
 
+	mux.HandleFunc("POST /api/users/{userID}/follow", apiCfg.handlerFollowsCreate)
+	mux.HandleFunc("DELETE /api/users/{userID}/follow", apiCfg.handlerFollowsDelete)
+	mux.HandleFunc("GET /api/users/{userID}/followers", apiCfg.handlerFollowersGet)
+	mux.HandleFunc("GET /api/users/{userID}/following", apiCfg.handlerFollowingGet)
+	mux.HandleFunc("POST /api/users/{userID}/reports", apiCfg.handlerUserReportsCreate)
+	mux.HandleFunc("GET /api/timeline", apiCfg.handlerTimeline)
+
+	mux.HandleFunc("POST /api/users/{userID}/block", apiCfg.handlerBlocksCreate)
+	mux.HandleFunc("DELETE /api/users/{userID}/block", apiCfg.handlerBlocksDelete)
+	mux.HandleFunc("GET /api/blocks", apiCfg.handlerBlocksGet)
+	mux.HandleFunc("POST /api/users/{userID}/mute", apiCfg.handlerMutesCreate)
+	mux.HandleFunc("DELETE /api/users/{userID}/mute", apiCfg.handlerMutesDelete)
+	mux.HandleFunc("GET /api/mutes", apiCfg.handlerMutesGet)
+
+	mux.HandleFunc("GET /api/notifications", apiCfg.handlerNotificationsGet)
+	mux.HandleFunc("GET /api/notifications/unread_count", apiCfg.handlerNotificationsUnreadCount)
+	mux.HandleFunc("POST /api/notifications/read", apiCfg.handlerNotificationsRead)
+
+	mux.HandleFunc("POST /api/conversations", apiCfg.handlerConversationsCreate)
+	mux.HandleFunc("GET /api/conversations", apiCfg.handlerConversationsGet)
+	mux.HandleFunc("GET /api/conversations/{conversationID}/messages", apiCfg.handlerMessagesGet)
+	mux.HandleFunc("POST /api/conversations/{conversationID}/messages", apiCfg.handlerMessagesCreate)
+	mux.HandleFunc("POST /api/conversations/{conversationID}/read", apiCfg.handlerConversationsRead)
+
+	mux.HandleFunc("GET /api/search/chirps", apiCfg.handlerSearchChirps)
+
+	mux.HandleFunc("GET /api/stream", apiCfg.handlerStream)
+	mux.HandleFunc("GET /api/ws", apiCfg.handlerWebSocket)
+
+	mux.HandleFunc("GET /api/hashtags/{tag}/chirps", apiCfg.handlerHashtagChirps)
+	mux.HandleFunc("GET /api/trending", apiCfg.handlerTrending)
+
+	mux.HandleFunc("POST /api/media", apiCfg.handlerMediaCreate)
+	mux.HandleFunc("PATCH /api/media/{mediaID}", apiCfg.handlerMediaUpdate)
+
 	mux.HandleFunc("POST /api/chirps", apiCfg.handlerChirpsCreate)
 	mux.HandleFunc("GET /api/chirps", apiCfg.handlerChirpsRetrieve)
 	mux.HandleFunc("GET /api/chirps/{chirpID}", apiCfg.handlerChirpsGet)
+	mux.HandleFunc("PATCH /api/chirps/{chirpID}", apiCfg.handlerChirpsUpdate)
+	mux.HandleFunc("GET /api/chirps/{chirpID}/history", apiCfg.handlerChirpsHistory)
+	mux.HandleFunc("GET /api/chirps/{chirpID}/thread", apiCfg.handlerChirpsThread)
 	mux.HandleFunc("DELETE /api/chirps/{chirpID}", apiCfg.handlerChirpsDelete)
-
-	mux.HandleFunc("POST /admin/reset", apiCfg.handlerReset)
-	mux.HandleFunc("GET /admin/metrics", apiCfg.handlerMetrics)
+	mux.HandleFunc("POST /api/chirps/{chirpID}/rechirps", apiCfg.handlerChirpsRechirp)
+	mux.HandleFunc("POST /api/chirps/{chirpID}/likes", apiCfg.handlerChirpsLike)
+	mux.HandleFunc("DELETE /api/chirps/{chirpID}/likes", apiCfg.handlerChirpsUnlike)
+	mux.HandleFunc("POST /api/chirps/{chirpID}/poll/votes", apiCfg.handlerPollVotesCreate)
+	mux.HandleFunc("POST /api/chirps/{chirpID}/reports", apiCfg.handlerChirpReportsCreate)
+
+	mux.Handle("POST /admin/reset", apiCfg.middlewareRequirePermission(rbac.PermReset, http.HandlerFunc(apiCfg.handlerReset)))
+	mux.Handle("GET /admin/metrics", apiCfg.middlewareRequirePermission(rbac.PermViewMetrics, http.HandlerFunc(apiCfg.handlerMetrics)))
+
+	mux.Handle("GET /admin/reports", apiCfg.middlewareRequirePermission(rbac.PermModerate, http.HandlerFunc(apiCfg.handlerReportsGet)))
+	mux.Handle("GET /admin/reports/{reportID}", apiCfg.middlewareRequirePermission(rbac.PermModerate, http.HandlerFunc(apiCfg.handlerReportGet)))
+	mux.Handle("POST /admin/reports/{reportID}/decisions", apiCfg.middlewareRequirePermission(rbac.PermModerate, http.HandlerFunc(apiCfg.handlerReportDecisionsCreate)))
+	mux.Handle("GET /admin/decisions", apiCfg.middlewareRequirePermission(rbac.PermModerate, http.HandlerFunc(apiCfg.handlerModerationDecisionsGet)))
+
+	mux.Handle("GET /admin/roles", apiCfg.middlewareRequirePermission(rbac.PermManageRoles, http.HandlerFunc(apiCfg.handlerRolesGet)))
+	mux.Handle("POST /admin/users/{userID}/roles", apiCfg.middlewareRequirePermission(rbac.PermManageRoles, http.HandlerFunc(apiCfg.handlerUserRolesCreate)))
+	mux.Handle("DELETE /admin/users/{userID}/roles/{role}", apiCfg.middlewareRequirePermission(rbac.PermManageRoles, http.HandlerFunc(apiCfg.handlerUserRolesDelete)))
+
+	mux.Handle("GET /admin/filters", apiCfg.middlewareRequirePermission(rbac.PermManageFilters, http.HandlerFunc(apiCfg.handlerContentFiltersGet)))
+	mux.Handle("PUT /admin/filters/{name}", apiCfg.middlewareRequirePermission(rbac.PermManageFilters, http.HandlerFunc(apiCfg.handlerContentFiltersPut)))
+	mux.Handle("DELETE /admin/filters/{name}", apiCfg.middlewareRequirePermission(rbac.PermManageFilters, http.HandlerFunc(apiCfg.handlerContentFiltersDelete)))
+	mux.Handle("POST /admin/filters/reload", apiCfg.middlewareRequirePermission(rbac.PermManageFilters, http.HandlerFunc(apiCfg.handlerContentFiltersReload)))
+
+	mux.Handle("POST /admin/tokens/denylist", apiCfg.middlewareRequirePermission(rbac.PermDenyTokens, http.HandlerFunc(apiCfg.handlerTokenDenylistCreate)))
 
 	srv := &http.Server{
 		Addr:    ":" + port,
@@ -0,0 +1,132 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"log"
+	"os"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/srinivassivaratri/Chirpy/internal/database"
+	"github.com/srinivassivaratri/Chirpy/internal/storage"
+)
+
+const (
+	// Most images one chirp can have
+	maxChirpMedia = 4
+	// Longest alt text, in characters
+	maxAltTextLength = 1000
+	// How long an upload can wait to be attached to a chirp
+	mediaAttachWindow = 24 * time.Hour
+	// How often uploads nobody needs any more are deleted
+	mediaCleanupInterval = time.Hour
+	// Most uploads deleted each time round
+	mediaCleanupBatchSize = 100
+)
+
+var errMediaNotFound = errors.New("Couldn't find media to attach")
+
+// ChirpMedia is an image attached to a chirp. Dimensions are in pixels.
+type ChirpMedia struct {
+	ID              uuid.UUID `json:"id"`
+	URL             string    `json:"url"`
+	ContentType     string    `json:"content_type"`
+	Width           int32     `json:"width"`
+	Height          int32     `json:"height"`
+	AltText         string    `json:"alt_text"`
+	ThumbnailURL    string    `json:"thumbnail_url"`
+	ThumbnailWidth  int32     `json:"thumbnail_width"`
+	ThumbnailHeight int32     `json:"thumbnail_height"`
+}
+
+func (cfg *apiConfig) databaseMediaToChirpMedia(dbMedia database.Medium) ChirpMedia {
+	return ChirpMedia{
+		ID:              dbMedia.ID,
+		URL:             cfg.mediaStorage.URL(dbMedia.StorageKey),
+		ContentType:     dbMedia.ContentType,
+		Width:           dbMedia.Width,
+		Height:          dbMedia.Height,
+		AltText:         dbMedia.AltText,
+		ThumbnailURL:    cfg.mediaStorage.URL(dbMedia.ThumbnailKey),
+		ThumbnailWidth:  dbMedia.ThumbnailWidth,
+		ThumbnailHeight: dbMedia.ThumbnailHeight,
+	}
+}
+
+// newMediaStorage picks where uploads are kept from the environment.
+// MEDIA_STORAGE is "local" (the default) or "s3".
+func newMediaStorage() (storage.Storage, error) {
+	switch os.Getenv("MEDIA_STORAGE") {
+	case "", "local":
+		dir := os.Getenv("MEDIA_DIR")
+		if dir == "" {
+			dir = "media"
+		}
+		baseURL := os.Getenv("MEDIA_BASE_URL")
+		if baseURL == "" {
+			baseURL = "/media"
+		}
+		return storage.NewLocal(dir, baseURL)
+	case "s3":
+		cfg := storage.S3Config{
+			Endpoint:        os.Getenv("S3_ENDPOINT"),
+			Bucket:          os.Getenv("S3_BUCKET"),
+			Region:          os.Getenv("S3_REGION"),
+			AccessKeyID:     os.Getenv("S3_ACCESS_KEY_ID"),
+			SecretAccessKey: os.Getenv("S3_SECRET_ACCESS_KEY"),
+			PublicURL:       os.Getenv("S3_PUBLIC_URL"),
+		}
+		if cfg.Endpoint == "" || cfg.Bucket == "" || cfg.AccessKeyID == "" || cfg.SecretAccessKey == "" {
+			return nil, errors.New("S3_ENDPOINT, S3_BUCKET, S3_ACCESS_KEY_ID and S3_SECRET_ACCESS_KEY must be set")
+		}
+		if cfg.Region == "" {
+			cfg.Region = "us-east-1"
+		}
+		return storage.NewS3(cfg), nil
+	}
+	return nil, errors.New("MEDIA_STORAGE must be local or s3")
+}
+
+// cleanupMediaLoop deletes the files and rows of uploads whose chirp was
+// deleted, or that were never attached to one
+func (cfg *apiConfig) cleanupMediaLoop(ctx context.Context) {
+	ticker := time.NewTicker(mediaCleanupInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		detached, err := cfg.db.ListDetachedMedia(ctx, database.ListDetachedMediaParams{
+			Cutoff:   time.Now().UTC().Add(-mediaAttachWindow),
+			RowLimit: mediaCleanupBatchSize,
+		})
+		if err != nil {
+			log.Printf("Error listing detached media: %s", err)
+			continue
+		}
+		for _, dbMedia := range detached {
+			err := cfg.deleteMedia(ctx, dbMedia)
+			if err != nil {
+				log.Printf("Error deleting media %s: %s", dbMedia.ID, err)
+			}
+		}
+	}
+}
+
+// deleteMedia deletes an upload's files, then its row. If deleting a file
+// fails the row stays so it's tried again.
+func (cfg *apiConfig) deleteMedia(ctx context.Context, dbMedia database.Medium) error {
+	err := cfg.mediaStorage.Delete(ctx, dbMedia.StorageKey)
+	if err != nil {
+		return err
+	}
+	err = cfg.mediaStorage.Delete(ctx, dbMedia.ThumbnailKey)
+	if err != nil {
+		return err
+	}
+	return cfg.db.DeleteMedia(ctx, dbMedia.ID)
+}
@@ -0,0 +1,117 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/srinivassivaratri/Chirpy/internal/database"
+	"github.com/srinivassivaratri/Chirpy/internal/rbac"
+)
+
+type Report struct {
+	ID         uuid.UUID  `json:"id"`
+	CreatedAt  time.Time  `json:"created_at"`
+	ReporterID *uuid.UUID `json:"reporter_id"`
+	Target     string     `json:"target"`
+	ChirpID    *uuid.UUID `json:"chirp_id"`
+	UserID     uuid.UUID  `json:"user_id"`
+	Reason     string     `json:"reason"`
+	Details    string     `json:"details"`
+	DecisionID *uuid.UUID `json:"decision_id"`
+	// Chirp is the reported chirp as it is now, only shown to moderators.
+	// It's a ChirpTombstone once the chirp is deleted.
+	Chirp interface{} `json:"chirp,omitempty"`
+}
+
+type ModerationDecision struct {
+	ID          uuid.UUID  `json:"id"`
+	CreatedAt   time.Time  `json:"created_at"`
+	ModeratorID *uuid.UUID `json:"moderator_id"`
+	ReportID    uuid.UUID  `json:"report_id"`
+	Target      string     `json:"target"`
+	ChirpID     *uuid.UUID `json:"chirp_id"`
+	UserID      uuid.UUID  `json:"user_id"`
+	Action      string     `json:"action"`
+	Note        string     `json:"note"`
+}
+
+func databaseReportToReport(dbReport database.Report) Report {
+	report := Report{
+		ID:        dbReport.ID,
+		CreatedAt: dbReport.CreatedAt,
+		Target:    dbReport.TargetKind,
+		UserID:    dbReport.UserID,
+		Reason:    dbReport.Reason,
+		Details:   dbReport.Details,
+	}
+	if dbReport.ReporterID.Valid {
+		reporterID := dbReport.ReporterID.UUID
+		report.ReporterID = &reporterID
+	}
+	if dbReport.ChirpID.Valid {
+		chirpID := dbReport.ChirpID.UUID
+		report.ChirpID = &chirpID
+	}
+	if dbReport.DecisionID.Valid {
+		decisionID := dbReport.DecisionID.UUID
+		report.DecisionID = &decisionID
+	}
+	return report
+}
+
+func databaseDecisionToDecision(dbDecision database.ModerationDecision) ModerationDecision {
+	decision := ModerationDecision{
+		ID:        dbDecision.ID,
+		CreatedAt: dbDecision.CreatedAt,
+		ReportID:  dbDecision.ReportID,
+		Target:    dbDecision.TargetKind,
+		UserID:    dbDecision.UserID,
+		Action:    dbDecision.Action,
+		Note:      dbDecision.Note,
+	}
+	if dbDecision.ModeratorID.Valid {
+		moderatorID := dbDecision.ModeratorID.UUID
+		decision.ModeratorID = &moderatorID
+	}
+	if dbDecision.ChirpID.Valid {
+		chirpID := dbDecision.ChirpID.UUID
+		decision.ChirpID = &chirpID
+	}
+	return decision
+}
+
+func (cfg *apiConfig) isModerator(ctx context.Context, viewerID uuid.NullUUID) (bool, error) {
+	if !viewerID.Valid {
+		return false, nil
+	}
+	role, err := cfg.userRole(ctx, viewerID.UUID)
+	if err != nil {
+		return false, err
+	}
+	return role.Can(rbac.PermModerate), nil
+}
+
+// respondIfHidden handles requests for a chirp a moderator has hidden and
+// returns true if it did. The author is told it's unavailable, anyone else
+// can't tell it exists, and moderators see it as usual.
+func (cfg *apiConfig) respondIfHidden(w http.ResponseWriter, r *http.Request, viewerID uuid.NullUUID, dbChirp database.Chirp) bool {
+	if !dbChirp.HiddenAt.Valid {
+		return false
+	}
+	moderator, err := cfg.isModerator(r.Context(), viewerID)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't get user", err)
+		return true
+	}
+	if moderator {
+		return false
+	}
+	if viewerID.Valid && viewerID.UUID == dbChirp.UserID {
+		respondWithError(w, http.StatusUnavailableForLegalReasons, "This chirp was hidden by a moderator", nil)
+		return true
+	}
+	respondWithError(w, http.StatusNotFound, "Couldn't get chirp", nil)
+	return true
+}
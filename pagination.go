@@ -0,0 +1,46 @@
+package main
+
+import (
+	"database/sql"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/srinivassivaratri/Chirpy/internal/pagination"
+)
+
+// parsePage reads the limit and cursor query parameters shared by every paged endpoint
+func parsePage(r *http.Request) (int, *pagination.Cursor, error) {
+	limit, err := pagination.ParseLimit(r.URL.Query().Get("limit"))
+	if err != nil {
+		return 0, nil, err
+	}
+
+	cursorStr := r.URL.Query().Get("cursor")
+	if cursorStr == "" {
+		return limit, nil, nil
+	}
+	cursor, err := pagination.Decode(cursorStr)
+	if err != nil {
+		return 0, nil, err
+	}
+	return limit, &cursor, nil
+}
+
+// cursorPosition turns a cursor into the nullable query parameters the list queries take
+func cursorPosition(cursor *pagination.Cursor) (sql.NullTime, uuid.NullUUID) {
+	if cursor == nil {
+		return sql.NullTime{}, uuid.NullUUID{}
+	}
+	return sql.NullTime{Time: cursor.CreatedAt, Valid: true}, uuid.NullUUID{UUID: cursor.ID, Valid: true}
+}
+
+// walkingBack reports whether the page has to be read against the list's sort order
+func walkingBack(cursor *pagination.Cursor) bool {
+	return cursor != nil && cursor.Direction == pagination.DirectionPrev
+}
+
+func setLinkHeader(w http.ResponseWriter, r *http.Request, nextCursor, prevCursor string) {
+	if link := pagination.LinkHeader(r.URL, nextCursor, prevCursor); link != "" {
+		w.Header().Set("Link", link)
+	}
+}
@@ -0,0 +1,160 @@
+package main
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/srinivassivaratri/Chirpy/internal/database"
+	"github.com/srinivassivaratri/Chirpy/internal/filter"
+	"github.com/srinivassivaratri/Chirpy/internal/notifications"
+	"github.com/srinivassivaratri/Chirpy/internal/polls"
+)
+
+const (
+	// How often closed polls are looked for
+	pollCloseInterval = 30 * time.Second
+	// Most closed polls handled each time round
+	pollCloseBatchSize = 100
+)
+
+// ChirpPoll is a poll on a chirp. Votes and TotalVotes are null until the
+// viewer has voted or the poll has closed. MyVote is the index of the
+// option the viewer picked, if they have.
+type ChirpPoll struct {
+	ClosesAt   time.Time    `json:"closes_at"`
+	Closed     bool         `json:"closed"`
+	Options    []PollOption `json:"options"`
+	TotalVotes *int64       `json:"total_votes"`
+	MyVote     *int32       `json:"my_vote"`
+}
+
+type PollOption struct {
+	Index int32  `json:"index"`
+	Text  string `json:"text"`
+	Votes *int64 `json:"votes"`
+}
+
+// newPoll is a poll to create along with its chirp
+type newPoll struct {
+	Options  []string
+	ClosesAt time.Time
+}
+
+// checkPoll validates a poll for a new chirp and runs its options through
+// the content filters. Masked words are masked in the returned options too.
+func (cfg *apiConfig) checkPoll(options []string, durationSeconds int) (*newPoll, []filter.Match, error) {
+	duration := time.Duration(durationSeconds) * time.Second
+	err := polls.Validate(options, duration)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	poll := &newPoll{ClosesAt: time.Now().UTC().Add(duration)}
+	flagged := []filter.Match{}
+	for _, option := range options {
+		result, err := cfg.contentFilter.Apply(option)
+		if err != nil {
+			return nil, nil, errBodyRejected
+		}
+		poll.Options = append(poll.Options, result.Text)
+		flagged = append(flagged, result.Flagged...)
+	}
+	return poll, flagged, nil
+}
+
+// pollsForViewer loads the polls on chirpIDs, keyed by chirp, with tallies
+// only where the viewer is allowed to see them
+func (cfg *apiConfig) pollsForViewer(ctx context.Context, viewerID uuid.NullUUID, chirpIDs []uuid.UUID) (map[uuid.UUID]*ChirpPoll, error) {
+	dbPolls, err := cfg.db.ListPolls(ctx, chirpIDs)
+	if err != nil {
+		return nil, err
+	}
+	if len(dbPolls) == 0 {
+		return map[uuid.UUID]*ChirpPoll{}, nil
+	}
+	pollIDs := []uuid.UUID{}
+	for _, dbPoll := range dbPolls {
+		pollIDs = append(pollIDs, dbPoll.ChirpID)
+	}
+
+	dbOptions, err := cfg.db.ListPollOptions(ctx, pollIDs)
+	if err != nil {
+		return nil, err
+	}
+	myVotes := map[uuid.UUID]int32{}
+	if viewerID.Valid {
+		dbVotes, err := cfg.db.ListPollVotesByUser(ctx, database.ListPollVotesByUserParams{
+			UserID:   viewerID.UUID,
+			ChirpIds: pollIDs,
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, dbVote := range dbVotes {
+			myVotes[dbVote.ChirpID] = dbVote.Position
+		}
+	}
+
+	now := time.Now().UTC()
+	chirpPolls := map[uuid.UUID]*ChirpPoll{}
+	for _, dbPoll := range dbPolls {
+		poll := &ChirpPoll{
+			ClosesAt: dbPoll.ClosesAt,
+			Closed:   polls.Closed(dbPoll.ClosesAt, now),
+			Options:  []PollOption{},
+		}
+		myVote, voted := myVotes[dbPoll.ChirpID]
+		if voted {
+			poll.MyVote = &myVote
+		}
+		if polls.ResultsVisible(voted, dbPoll.ClosesAt, now) {
+			var total int64
+			poll.TotalVotes = &total
+		}
+		chirpPolls[dbPoll.ChirpID] = poll
+	}
+	for _, dbOption := range dbOptions {
+		poll := chirpPolls[dbOption.ChirpID]
+		option := PollOption{
+			Index: dbOption.Position,
+			Text:  dbOption.Text,
+		}
+		if poll.TotalVotes != nil {
+			votes := dbOption.VoteCount
+			option.Votes = &votes
+			*poll.TotalVotes += votes
+		}
+		poll.Options = append(poll.Options, option)
+	}
+	return chirpPolls, nil
+}
+
+// closePollsLoop tells authors when their polls close. Voting is already
+// refused once a poll's closing time passes, so this only sends the
+// notification.
+func (cfg *apiConfig) closePollsLoop(ctx context.Context) {
+	ticker := time.NewTicker(pollCloseInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		closed, err := cfg.db.ClaimClosedPolls(ctx, pollCloseBatchSize)
+		if err != nil {
+			log.Printf("Error claiming closed polls: %s", err)
+			continue
+		}
+		for _, row := range closed {
+			cfg.notifier.Notify(notifications.Notification{
+				UserID:  row.UserID,
+				Kind:    notifications.KindPollClosed,
+				ChirpID: uuid.NullUUID{UUID: row.ID, Valid: true},
+			})
+		}
+	}
+}
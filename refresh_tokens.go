@@ -0,0 +1,105 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/srinivassivaratri/Chirpy/internal/auth"
+	"github.com/srinivassivaratri/Chirpy/internal/database"
+)
+
+// How long a refresh token lasts. Each refresh starts the clock again.
+const refreshTokenLifetime = 60 * 24 * time.Hour
+
+var (
+	errRefreshTokenInvalid = errors.New("Refresh token is invalid or expired")
+	errRefreshTokenReused  = errors.New("Refresh token was already used, log in again")
+)
+
+// createRefreshToken saves a new refresh token for userID in a session.
+// Together a session's refresh tokens make up one token family.
+func createRefreshToken(ctx context.Context, q *database.Queries, userID, sessionID uuid.UUID) (database.RefreshToken, error) {
+	token, err := auth.MakeRefreshToken()
+	if err != nil {
+		return database.RefreshToken{}, err
+	}
+	return q.CreateRefreshToken(ctx, database.CreateRefreshTokenParams{
+		Token:     token,
+		UserID:    userID,
+		ExpiresAt: time.Now().UTC().Add(refreshTokenLifetime),
+		SessionID: sessionID,
+	})
+}
+
+// rotateRefreshToken marks a refresh token used and returns the one that
+// replaces it, recording the device it came from on the session. Presenting
+// a token that was already used revokes its session and returns
+// errRefreshTokenReused: either the client or someone who copied the token
+// has moved on without the other, and we can't tell which.
+func (cfg *apiConfig) rotateRefreshToken(ctx context.Context, token string, device deviceInfo) (database.RefreshToken, error) {
+	tx, err := cfg.dbConn.BeginTx(ctx, nil)
+	if err != nil {
+		return database.RefreshToken{}, err
+	}
+	defer tx.Rollback()
+	qtx := cfg.db.WithTx(tx)
+
+	used, err := qtx.UseRefreshToken(ctx, token)
+	if errors.Is(err, sql.ErrNoRows) {
+		return database.RefreshToken{}, revokeIfReused(ctx, qtx, tx, token)
+	}
+	if err != nil {
+		return database.RefreshToken{}, err
+	}
+
+	replacement, err := createRefreshToken(ctx, qtx, used.UserID, used.SessionID)
+	if err != nil {
+		return database.RefreshToken{}, err
+	}
+	err = qtx.TouchSession(ctx, database.TouchSessionParams{
+		ID:        used.SessionID,
+		UserAgent: device.UserAgent,
+		IpAddress: device.IPAddress,
+		ExpiresAt: replacement.ExpiresAt,
+	})
+	if err != nil {
+		return database.RefreshToken{}, err
+	}
+	err = tx.Commit()
+	if err != nil {
+		return database.RefreshToken{}, err
+	}
+	return replacement, nil
+}
+
+// revokeIfReused works out why a token couldn't be used. If it was used
+// before, it revokes the token's session and commits tx.
+func revokeIfReused(ctx context.Context, qtx *database.Queries, tx *sql.Tx, token string) error {
+	existing, err := qtx.GetRefreshToken(ctx, token)
+	if errors.Is(err, sql.ErrNoRows) {
+		return errRefreshTokenInvalid
+	}
+	if err != nil {
+		return err
+	}
+	if !existing.UsedAt.Valid {
+		return errRefreshTokenInvalid
+	}
+
+	revoked, err := qtx.RevokeSessionByRefreshToken(ctx, token)
+	if err != nil {
+		return err
+	}
+	err = tx.Commit()
+	if err != nil {
+		return err
+	}
+	if revoked > 0 {
+		log.Printf("Refresh token reused for user %s, revoked session %s", existing.UserID, existing.SessionID)
+	}
+	return errRefreshTokenReused
+}
@@ -12,5 +12,5 @@ func (cfg *apiConfig) handlerReset(w http.ResponseWriter, r *http.Request) {
 	cfg.fileserverHits.Store(0)
 	cfg.db.Reset(r.Context())
 	w.WriteHeader(http.StatusOK)
-	w.Write([]byte("Hits reset to 0 and database reset to initial state."))
+	w.Write([]byte("Hits reset to 0 and every user but the admins deleted."))
 }
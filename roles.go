@@ -0,0 +1,81 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/srinivassivaratri/Chirpy/internal/auth"
+	"github.com/srinivassivaratri/Chirpy/internal/rbac"
+)
+
+type contextKey string
+
+const userIDContextKey contextKey = "userID"
+
+// userRole looks up the role a user has right now. Roles aren't put in the
+// JWT so revoking one takes effect on the next request.
+func (cfg *apiConfig) userRole(ctx context.Context, userID uuid.UUID) (rbac.Role, error) {
+	user, err := cfg.db.GetUser(ctx, userID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return rbac.RoleUser, nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return rbac.Role(user.Role), nil
+}
+
+// middlewareRequirePermission only lets requests through from users whose
+// role has the permission. Handlers behind it get the caller from
+// requestUserID.
+func (cfg *apiConfig) middlewareRequirePermission(perm rbac.Permission, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token, err := auth.GetBearerToken(r.Header)
+		if err != nil {
+			respondWithAuthError(w, err)
+			return
+		}
+		userID, err := cfg.validateJWT(r.Context(), token)
+		if err != nil {
+			respondWithAuthError(w, err)
+			return
+		}
+
+		role, err := cfg.userRole(r.Context(), userID)
+		if err != nil {
+			respondWithError(w, http.StatusInternalServerError, "Couldn't get user", err)
+			return
+		}
+		if !role.Can(perm) {
+			respondWithError(w, http.StatusForbidden, "You don't have permission to do that", nil)
+			return
+		}
+
+		ctx := context.WithValue(r.Context(), userIDContextKey, userID)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// requestUserID returns the caller set by middlewareRequirePermission
+func requestUserID(r *http.Request) uuid.UUID {
+	userID, _ := r.Context().Value(userIDContextKey).(uuid.UUID)
+	return userID
+}
+
+// bootstrapAdmin makes the existing user with the configured admin email an
+// admin, as long as nobody is one yet. It only runs at startup: promoting on
+// sign up would hand admin to whoever registers the address first, since
+// emails aren't verified. It reports whether it did.
+func (cfg *apiConfig) bootstrapAdmin(ctx context.Context) (bool, error) {
+	if cfg.adminEmail == "" {
+		return false, nil
+	}
+	rows, err := cfg.db.BootstrapAdmin(ctx, cfg.adminEmail)
+	if err != nil {
+		return false, err
+	}
+	return rows > 0, nil
+}
@@ -0,0 +1,122 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/srinivassivaratri/Chirpy/internal/auth"
+	"github.com/srinivassivaratri/Chirpy/internal/database"
+)
+
+// Longest user agent kept for a session, in bytes
+const maxUserAgentLength = 512
+
+var errSessionRevoked = fmt.Errorf("session has been revoked: %w", auth.ErrTokenRevoked)
+
+// Session is one device a user is logged in on
+type Session struct {
+	ID         uuid.UUID `json:"id"`
+	CreatedAt  time.Time `json:"created_at"`
+	LastUsedAt time.Time `json:"last_used_at"`
+	ExpiresAt  time.Time `json:"expires_at"`
+	UserAgent  string    `json:"user_agent"`
+	IPAddress  string    `json:"ip_address"`
+	// Current is the session the request was made with
+	Current bool `json:"current"`
+}
+
+func databaseSessionToSession(dbSession database.Session, currentID uuid.UUID) Session {
+	return Session{
+		ID:         dbSession.ID,
+		CreatedAt:  dbSession.CreatedAt,
+		LastUsedAt: dbSession.LastUsedAt,
+		ExpiresAt:  dbSession.ExpiresAt,
+		UserAgent:  dbSession.UserAgent,
+		IPAddress:  dbSession.IpAddress,
+		Current:    dbSession.ID == currentID,
+	}
+}
+
+// deviceInfo is what a session shows about the device using it
+type deviceInfo struct {
+	UserAgent string
+	IPAddress string
+}
+
+// requestDevice reads the device a request came from. The IP is the
+// connection's, so behind a proxy it's the proxy's.
+func requestDevice(r *http.Request) deviceInfo {
+	userAgent := strings.ToValidUTF8(r.UserAgent(), "")
+	if len(userAgent) > maxUserAgentLength {
+		userAgent = strings.ToValidUTF8(userAgent[:maxUserAgentLength], "")
+	}
+	ip, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		ip = r.RemoteAddr
+	}
+	return deviceInfo{UserAgent: userAgent, IPAddress: ip}
+}
+
+// startSession logs a user in on a new device, saving the session and its
+// first refresh token together
+func (cfg *apiConfig) startSession(ctx context.Context, userID uuid.UUID, device deviceInfo) (database.RefreshToken, error) {
+	tx, err := cfg.dbConn.BeginTx(ctx, nil)
+	if err != nil {
+		return database.RefreshToken{}, err
+	}
+	defer tx.Rollback()
+	qtx := cfg.db.WithTx(tx)
+
+	session, err := qtx.CreateSession(ctx, database.CreateSessionParams{
+		UserID:    userID,
+		UserAgent: device.UserAgent,
+		IpAddress: device.IPAddress,
+		ExpiresAt: time.Now().UTC().Add(refreshTokenLifetime),
+	})
+	if err != nil {
+		return database.RefreshToken{}, err
+	}
+	refreshToken, err := createRefreshToken(ctx, qtx, userID, session.ID)
+	if err != nil {
+		return database.RefreshToken{}, err
+	}
+	err = tx.Commit()
+	if err != nil {
+		return database.RefreshToken{}, err
+	}
+	return refreshToken, nil
+}
+
+// authenticate validates an access JWT and checks its session hasn't been
+// revoked since it was issued
+func (cfg *apiConfig) authenticate(ctx context.Context, token string) (auth.Claims, error) {
+	claims, err := auth.ParseJWT(ctx, token, cfg.jwtConfig)
+	if err != nil {
+		return auth.Claims{}, err
+	}
+	active, err := cfg.db.IsSessionActive(ctx, database.IsSessionActiveParams{
+		ID:     claims.SessionID,
+		UserID: claims.UserID,
+	})
+	if err != nil {
+		return auth.Claims{}, err
+	}
+	if !active {
+		return auth.Claims{}, errSessionRevoked
+	}
+	return claims, nil
+}
+
+// validateJWT is authenticate for callers that only need the user
+func (cfg *apiConfig) validateJWT(ctx context.Context, token string) (uuid.UUID, error) {
+	claims, err := cfg.authenticate(ctx, token)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	return claims.UserID, nil
+}
@@ -0,0 +1,76 @@
+-- name: CreateBlock :exec
+INSERT INTO blocks (blocker_id, blocked_id, created_at)
+VALUES (
+    $1,
+    $2,
+    NOW()
+)
+ON CONFLICT DO NOTHING;
+
+-- name: DeleteBlock :exec
+DELETE FROM blocks
+WHERE blocker_id = $1 AND blocked_id = $2;
+
+-- name: DeleteFollowsBetween :exec
+-- Blocking someone ends their follow of you and yours of them
+DELETE FROM follows
+WHERE (follower_id = sqlc.arg('user_id') AND followee_id = sqlc.arg('other_id'))
+OR (follower_id = sqlc.arg('other_id') AND followee_id = sqlc.arg('user_id'));
+
+-- name: IsBlocked :one
+SELECT EXISTS (
+    SELECT 1 FROM blocks
+    WHERE blocker_id = $1 AND blocked_id = $2
+);
+
+-- name: IsBlockedEitherWay :one
+SELECT EXISTS (
+    SELECT 1 FROM blocks
+    WHERE (blocker_id = sqlc.arg('user_id') AND blocked_id = sqlc.arg('other_id'))
+    OR (blocker_id = sqlc.arg('other_id') AND blocked_id = sqlc.arg('user_id'))
+);
+
+-- name: ListBlockersOf :many
+-- Which of user_ids have blocked blocked_id
+SELECT blocker_id FROM blocks
+WHERE blocked_id = sqlc.arg('blocked_id')
+AND blocker_id = ANY(sqlc.arg('user_ids')::uuid[]);
+
+-- name: IsBlockedInConversation :one
+-- Whether the user and anyone else in the conversation have blocked each
+-- other, either way round
+SELECT EXISTS (
+    SELECT 1 FROM conversation_participants
+    JOIN blocks ON (
+        blocks.blocker_id = conversation_participants.user_id
+        AND blocks.blocked_id = sqlc.arg('user_id')
+    ) OR (
+        blocks.blocker_id = sqlc.arg('user_id')
+        AND blocks.blocked_id = conversation_participants.user_id
+    )
+    WHERE conversation_participants.conversation_id = sqlc.arg('conversation_id')
+);
+
+-- name: ListHiddenUserIDs :many
+SELECT hidden_user_id FROM hidden_users
+WHERE user_id = $1;
+
+-- name: ListBlocksAsc :many
+SELECT * FROM blocks
+WHERE blocker_id = sqlc.arg('user_id')
+AND (
+    sqlc.narg('cursor_created_at')::timestamp IS NULL
+    OR (created_at, blocked_id) > (sqlc.narg('cursor_created_at')::timestamp, sqlc.narg('cursor_id')::uuid)
+)
+ORDER BY created_at ASC, blocked_id ASC
+LIMIT sqlc.arg('row_limit');
+
+-- name: ListBlocksDesc :many
+SELECT * FROM blocks
+WHERE blocker_id = sqlc.arg('user_id')
+AND (
+    sqlc.narg('cursor_created_at')::timestamp IS NULL
+    OR (created_at, blocked_id) < (sqlc.narg('cursor_created_at')::timestamp, sqlc.narg('cursor_id')::uuid)
+)
+ORDER BY created_at DESC, blocked_id DESC
+LIMIT sqlc.arg('row_limit');
@@ -0,0 +1,27 @@
+-- name: CreateChirpEvent :exec
+-- Call it after a chirp's hashtags are saved, or before it's deleted, so the
+-- event carries the chirp's author, thread and tags
+INSERT INTO chirp_events (created_at, kind, chirp_id, user_id, tags, thread_id)
+SELECT NOW(), sqlc.arg('kind'), chirps.id, chirps.user_id, ARRAY(
+    SELECT chirp_hashtags.tag FROM chirp_hashtags
+    WHERE chirp_hashtags.chirp_id = chirps.id
+    ORDER BY chirp_hashtags.tag
+)::text[], chirps.thread_id
+FROM chirps
+WHERE chirps.id = sqlc.arg('chirp_id');
+
+-- name: GetLatestChirpEventID :one
+SELECT COALESCE(MAX(id), 0)::bigint AS latest_id FROM chirp_events;
+
+-- name: ListChirpEventsAfter :many
+-- settled says whether the event is old enough that any transaction that
+-- took an earlier ID has finished
+SELECT id, created_at, kind, chirp_id, user_id, tags, thread_id, created_at < NOW() - sqlc.arg('settle_seconds')::float8 * INTERVAL '1 second' AS settled
+FROM chirp_events
+WHERE id > sqlc.arg('after_id')
+ORDER BY id
+LIMIT sqlc.arg('row_limit');
+
+-- name: DeleteOldChirpEvents :exec
+DELETE FROM chirp_events
+WHERE created_at < NOW() - sqlc.arg('retention_seconds')::float8 * INTERVAL '1 second';
@@ -1,26 +1,144 @@
 -- name: CreateChirp :one
-INSERT INTO chirps (id, created_at, updated_at, body, user_id)
-VALUES (
-    gen_random_uuid(),
+WITH new_chirp AS (
+    SELECT gen_random_uuid() AS id
+), parent AS (
+    SELECT chirps.id, chirps.thread_id, chirps.ancestor_ids FROM chirps
+    WHERE chirps.id = sqlc.narg('in_reply_to')
+)
+INSERT INTO chirps (id, created_at, updated_at, body, user_id, in_reply_to, thread_id, ancestor_ids, rechirp_of)
+SELECT
+    new_chirp.id,
     NOW(),
     NOW(),
-    $1,
-    $2
-)
+    sqlc.arg('body'),
+    sqlc.arg('user_id'),
+    parent.id,
+    COALESCE(parent.thread_id, new_chirp.id),
+    COALESCE(parent.ancestor_ids || parent.id, '{}'),
+    sqlc.narg('rechirp_of')
+FROM new_chirp
+LEFT JOIN parent ON TRUE
 RETURNING *;
 
--- name: GetChirpsAsc :many
+-- name: ListChirpsAsc :many
 SELECT * FROM chirps
-ORDER BY created_at ASC;
+WHERE (sqlc.narg('author_id')::uuid IS NULL OR user_id = sqlc.narg('author_id'))
+AND (
+    sqlc.narg('cursor_created_at')::timestamp IS NULL
+    OR (created_at, id) > (sqlc.narg('cursor_created_at')::timestamp, sqlc.narg('cursor_id')::uuid)
+)
+AND NOT EXISTS (
+    SELECT 1 FROM hidden_users
+    WHERE hidden_users.user_id = sqlc.narg('viewer_id') AND hidden_users.hidden_user_id = chirps.user_id
+)
+AND hidden_at IS NULL
+ORDER BY created_at ASC, id ASC
+LIMIT sqlc.arg('row_limit');
 
--- name: GetChirpsDesc :many
+-- name: ListChirpsDesc :many
 SELECT * FROM chirps
-ORDER BY created_at DESC;
+WHERE (sqlc.narg('author_id')::uuid IS NULL OR user_id = sqlc.narg('author_id'))
+AND (
+    sqlc.narg('cursor_created_at')::timestamp IS NULL
+    OR (created_at, id) < (sqlc.narg('cursor_created_at')::timestamp, sqlc.narg('cursor_id')::uuid)
+)
+AND NOT EXISTS (
+    SELECT 1 FROM hidden_users
+    WHERE hidden_users.user_id = sqlc.narg('viewer_id') AND hidden_users.hidden_user_id = chirps.user_id
+)
+AND hidden_at IS NULL
+ORDER BY created_at DESC, id DESC
+LIMIT sqlc.arg('row_limit');
 
 -- name: GetChirp :one
 SELECT * FROM chirps
 WHERE id = $1;
 
+-- name: GetPlainRechirp :one
+SELECT * FROM chirps
+WHERE user_id = $1 AND rechirp_of = $2 AND body = '';
+
+-- name: UpdateChirpBody :one
+WITH revision AS (
+    INSERT INTO chirp_revisions (chirp_id, version, body, created_at, replaced_at)
+    SELECT chirps.id, chirps.version, chirps.body, chirps.updated_at, NOW() FROM chirps
+    WHERE chirps.id = sqlc.arg('id')
+)
+UPDATE chirps SET body = sqlc.arg('body'), version = version + 1, updated_at = NOW()
+WHERE id = sqlc.arg('id')
+RETURNING *;
+
 -- name: DeleteChirp :exec
 DELETE FROM chirps
 WHERE id = $1;
+
+-- name: HideChirp :one
+UPDATE chirps SET hidden_at = NOW()
+WHERE id = $1
+RETURNING *;
+
+-- name: GetChirpsByIDs :many
+SELECT * FROM chirps
+WHERE id = ANY(sqlc.arg('ids')::uuid[]);
+
+-- name: ListChirpRepliesAsc :many
+SELECT * FROM chirps
+WHERE ancestor_ids @> ARRAY[sqlc.arg('chirp_id')::uuid]
+AND (
+    sqlc.narg('cursor_created_at')::timestamp IS NULL
+    OR (created_at, id) > (sqlc.narg('cursor_created_at')::timestamp, sqlc.narg('cursor_id')::uuid)
+)
+AND NOT EXISTS (
+    SELECT 1 FROM hidden_users
+    WHERE hidden_users.user_id = sqlc.narg('viewer_id') AND hidden_users.hidden_user_id = chirps.user_id
+)
+AND hidden_at IS NULL
+ORDER BY created_at ASC, id ASC
+LIMIT sqlc.arg('row_limit');
+
+-- name: ListChirpRepliesDesc :many
+SELECT * FROM chirps
+WHERE ancestor_ids @> ARRAY[sqlc.arg('chirp_id')::uuid]
+AND (
+    sqlc.narg('cursor_created_at')::timestamp IS NULL
+    OR (created_at, id) < (sqlc.narg('cursor_created_at')::timestamp, sqlc.narg('cursor_id')::uuid)
+)
+AND NOT EXISTS (
+    SELECT 1 FROM hidden_users
+    WHERE hidden_users.user_id = sqlc.narg('viewer_id') AND hidden_users.hidden_user_id = chirps.user_id
+)
+AND hidden_at IS NULL
+ORDER BY created_at DESC, id DESC
+LIMIT sqlc.arg('row_limit');
+
+-- name: ListTimelineAsc :many
+SELECT chirps.* FROM chirps
+JOIN follows ON chirps.user_id = follows.followee_id
+WHERE follows.follower_id = sqlc.arg('user_id')
+AND (
+    sqlc.narg('cursor_created_at')::timestamp IS NULL
+    OR (chirps.created_at, chirps.id) > (sqlc.narg('cursor_created_at')::timestamp, sqlc.narg('cursor_id')::uuid)
+)
+AND NOT EXISTS (
+    SELECT 1 FROM hidden_users
+    WHERE hidden_users.user_id = sqlc.arg('user_id') AND hidden_users.hidden_user_id = chirps.user_id
+)
+AND chirps.hidden_at IS NULL
+ORDER BY chirps.created_at ASC, chirps.id ASC
+LIMIT sqlc.arg('row_limit');
+
+-- name: ListTimelineDesc :many
+SELECT chirps.* FROM chirps
+JOIN follows ON chirps.user_id = follows.followee_id
+WHERE follows.follower_id = sqlc.arg('user_id')
+AND (
+    sqlc.narg('cursor_created_at')::timestamp IS NULL
+    OR (chirps.created_at, chirps.id) < (sqlc.narg('cursor_created_at')::timestamp, sqlc.narg('cursor_id')::uuid)
+)
+AND NOT EXISTS (
+    SELECT 1 FROM hidden_users
+    WHERE hidden_users.user_id = sqlc.arg('user_id') AND hidden_users.hidden_user_id = chirps.user_id
+)
+AND chirps.hidden_at IS NULL
+ORDER BY chirps.created_at DESC, chirps.id DESC
+LIMIT sqlc.arg('row_limit');
@@ -0,0 +1,32 @@
+-- name: UpsertContentFilter :one
+INSERT INTO content_filters (name, created_at, updated_at, action)
+VALUES (
+    $1,
+    NOW(),
+    NOW(),
+    $2
+)
+ON CONFLICT (name) DO UPDATE
+SET action = EXCLUDED.action, updated_at = NOW()
+RETURNING *;
+
+-- name: DeleteContentFilter :execrows
+DELETE FROM content_filters
+WHERE name = $1;
+
+-- name: DeleteContentFilterWords :exec
+DELETE FROM content_filter_words
+WHERE filter_name = $1;
+
+-- name: AddContentFilterWords :exec
+INSERT INTO content_filter_words (filter_name, word)
+SELECT sqlc.arg('filter_name'), unnest(sqlc.arg('words')::text[])
+ON CONFLICT DO NOTHING;
+
+-- name: ListContentFilters :many
+SELECT * FROM content_filters
+ORDER BY name;
+
+-- name: ListContentFilterWords :many
+SELECT * FROM content_filter_words
+ORDER BY filter_name, word;
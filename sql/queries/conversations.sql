@@ -0,0 +1,96 @@
+-- name: CreateConversation :one
+INSERT INTO conversations (id, created_at, updated_at, dm_key)
+VALUES (
+    gen_random_uuid(),
+    NOW(),
+    NOW(),
+    $1
+)
+RETURNING *;
+
+-- name: AddConversationParticipants :exec
+INSERT INTO conversation_participants (conversation_id, user_id, joined_at)
+SELECT sqlc.arg('conversation_id'), unnest(sqlc.arg('user_ids')::uuid[]), NOW();
+
+-- name: GetDirectConversation :one
+SELECT * FROM conversations
+WHERE dm_key = $1;
+
+-- name: GetConversationForUser :one
+-- Only finds the conversation if the user is in it
+SELECT conversations.* FROM conversations
+JOIN conversation_participants ON conversations.id = conversation_participants.conversation_id
+WHERE conversations.id = sqlc.arg('id')
+AND conversation_participants.user_id = sqlc.arg('user_id');
+
+-- name: ListConversationsAsc :many
+SELECT conversations.*, (
+    SELECT COUNT(*) FROM messages
+    WHERE messages.conversation_id = conversations.id
+    AND messages.sender_id != sqlc.arg('user_id')
+    AND (
+        conversation_participants.last_read_at IS NULL
+        OR (messages.created_at, messages.id) > (conversation_participants.last_read_at, conversation_participants.last_read_message_id)
+    )
+) AS unread_count
+FROM conversations
+JOIN conversation_participants ON conversations.id = conversation_participants.conversation_id
+WHERE conversation_participants.user_id = sqlc.arg('user_id')
+AND (
+    sqlc.narg('cursor_updated_at')::timestamp IS NULL
+    OR (conversations.updated_at, conversations.id) > (sqlc.narg('cursor_updated_at')::timestamp, sqlc.narg('cursor_id')::uuid)
+)
+ORDER BY conversations.updated_at ASC, conversations.id ASC
+LIMIT sqlc.arg('row_limit');
+
+-- name: ListConversationsDesc :many
+SELECT conversations.*, (
+    SELECT COUNT(*) FROM messages
+    WHERE messages.conversation_id = conversations.id
+    AND messages.sender_id != sqlc.arg('user_id')
+    AND (
+        conversation_participants.last_read_at IS NULL
+        OR (messages.created_at, messages.id) > (conversation_participants.last_read_at, conversation_participants.last_read_message_id)
+    )
+) AS unread_count
+FROM conversations
+JOIN conversation_participants ON conversations.id = conversation_participants.conversation_id
+WHERE conversation_participants.user_id = sqlc.arg('user_id')
+AND (
+    sqlc.narg('cursor_updated_at')::timestamp IS NULL
+    OR (conversations.updated_at, conversations.id) < (sqlc.narg('cursor_updated_at')::timestamp, sqlc.narg('cursor_id')::uuid)
+)
+ORDER BY conversations.updated_at DESC, conversations.id DESC
+LIMIT sqlc.arg('row_limit');
+
+-- name: ListConversationParticipants :many
+SELECT conversation_participants.*, users.username
+FROM conversation_participants
+JOIN users ON conversation_participants.user_id = users.id
+WHERE conversation_participants.conversation_id = ANY(sqlc.arg('conversation_ids')::uuid[])
+ORDER BY conversation_participants.conversation_id, conversation_participants.joined_at, conversation_participants.user_id;
+
+-- name: CountUnreadMessages :one
+SELECT COUNT(*) FROM messages
+JOIN conversation_participants ON messages.conversation_id = conversation_participants.conversation_id
+WHERE conversation_participants.conversation_id = sqlc.arg('conversation_id')
+AND conversation_participants.user_id = sqlc.arg('user_id')
+AND messages.sender_id != sqlc.arg('user_id')
+AND (
+    conversation_participants.last_read_at IS NULL
+    OR (messages.created_at, messages.id) > (conversation_participants.last_read_at, conversation_participants.last_read_message_id)
+);
+
+-- name: MarkConversationRead :exec
+-- Moves the user's read receipt forward to the message, never back
+UPDATE conversation_participants
+SET last_read_message_id = messages.id, last_read_at = messages.created_at
+FROM messages
+WHERE messages.id = sqlc.arg('message_id')
+AND messages.conversation_id = conversation_participants.conversation_id
+AND conversation_participants.conversation_id = sqlc.arg('conversation_id')
+AND conversation_participants.user_id = sqlc.arg('user_id')
+AND (
+    conversation_participants.last_read_at IS NULL
+    OR (messages.created_at, messages.id) > (conversation_participants.last_read_at, conversation_participants.last_read_message_id)
+);
@@ -0,0 +1,15 @@
+-- name: DenyToken :exec
+INSERT INTO denied_tokens (jti, created_at, expires_at)
+VALUES ($1, NOW(), $2)
+ON CONFLICT (jti) DO UPDATE SET expires_at = GREATEST(denied_tokens.expires_at, EXCLUDED.expires_at);
+
+-- name: IsTokenDenied :one
+SELECT EXISTS (
+    SELECT 1 FROM denied_tokens
+    WHERE jti = $1
+    AND expires_at > NOW()
+);
+
+-- name: DeleteExpiredDeniedTokens :execrows
+DELETE FROM denied_tokens
+WHERE expires_at <= NOW();
@@ -0,0 +1,70 @@
+-- name: CreateHashtags :exec
+INSERT INTO hashtags (tag, created_at)
+SELECT unnest(sqlc.arg('tags')::text[]), NOW()
+ON CONFLICT DO NOTHING;
+
+-- name: AddChirpHashtags :exec
+INSERT INTO chirp_hashtags (chirp_id, tag, created_at)
+SELECT sqlc.arg('chirp_id'), unnest(sqlc.arg('tags')::text[]), sqlc.arg('created_at')
+ON CONFLICT DO NOTHING;
+
+-- name: DeleteChirpHashtags :exec
+DELETE FROM chirp_hashtags
+WHERE chirp_id = $1;
+
+-- name: ListHashtagChirpsAsc :many
+SELECT chirps.* FROM chirps
+JOIN chirp_hashtags ON chirps.id = chirp_hashtags.chirp_id
+WHERE chirp_hashtags.tag = sqlc.arg('tag')
+AND (
+    sqlc.narg('cursor_created_at')::timestamp IS NULL
+    OR (chirps.created_at, chirps.id) > (sqlc.narg('cursor_created_at')::timestamp, sqlc.narg('cursor_id')::uuid)
+)
+AND NOT EXISTS (
+    SELECT 1 FROM hidden_users
+    WHERE hidden_users.user_id = sqlc.narg('viewer_id') AND hidden_users.hidden_user_id = chirps.user_id
+)
+AND chirps.hidden_at IS NULL
+ORDER BY chirps.created_at ASC, chirps.id ASC
+LIMIT sqlc.arg('row_limit');
+
+-- name: ListHashtagChirpsDesc :many
+SELECT chirps.* FROM chirps
+JOIN chirp_hashtags ON chirps.id = chirp_hashtags.chirp_id
+WHERE chirp_hashtags.tag = sqlc.arg('tag')
+AND (
+    sqlc.narg('cursor_created_at')::timestamp IS NULL
+    OR (chirps.created_at, chirps.id) < (sqlc.narg('cursor_created_at')::timestamp, sqlc.narg('cursor_id')::uuid)
+)
+AND NOT EXISTS (
+    SELECT 1 FROM hidden_users
+    WHERE hidden_users.user_id = sqlc.narg('viewer_id') AND hidden_users.hidden_user_id = chirps.user_id
+)
+AND chirps.hidden_at IS NULL
+ORDER BY chirps.created_at DESC, chirps.id DESC
+LIMIT sqlc.arg('row_limit');
+
+-- name: CreateTrendingSnapshot :exec
+-- Each use of a tag inside the window counts for less the older it is,
+-- halving every half_life_seconds
+INSERT INTO trending_hashtags (tag, computed_at, score, chirp_count)
+SELECT
+    tag,
+    sqlc.arg('computed_at'),
+    SUM(POWER(0.5, EXTRACT(EPOCH FROM (NOW() - created_at)) / sqlc.arg('half_life_seconds')::float8)),
+    COUNT(*)
+FROM chirp_hashtags
+WHERE created_at > NOW() - make_interval(secs => sqlc.arg('window_seconds')::float8)
+GROUP BY tag
+ORDER BY 3 DESC
+LIMIT sqlc.arg('row_limit');
+
+-- name: DeleteTrendingSnapshotsBefore :exec
+DELETE FROM trending_hashtags
+WHERE computed_at < $1;
+
+-- name: ListTrendingHashtags :many
+SELECT * FROM trending_hashtags
+WHERE computed_at = (SELECT MAX(computed_at) FROM trending_hashtags)
+ORDER BY score DESC
+LIMIT $1;
@@ -0,0 +1,59 @@
+-- name: CreateMedia :one
+INSERT INTO media (
+    id, created_at, user_id, content_type, storage_key, width, height,
+    size_bytes, thumbnail_key, thumbnail_width, thumbnail_height, alt_text
+)
+VALUES (
+    sqlc.arg('id'),
+    NOW(),
+    sqlc.arg('user_id'),
+    sqlc.arg('content_type'),
+    sqlc.arg('storage_key'),
+    sqlc.arg('width'),
+    sqlc.arg('height'),
+    sqlc.arg('size_bytes'),
+    sqlc.arg('thumbnail_key'),
+    sqlc.arg('thumbnail_width'),
+    sqlc.arg('thumbnail_height'),
+    sqlc.arg('alt_text')
+)
+RETURNING *;
+
+-- name: GetMedia :one
+SELECT * FROM media
+WHERE id = $1;
+
+-- name: UpdateMediaAltText :one
+UPDATE media
+SET alt_text = $2
+WHERE id = $1
+RETURNING *;
+
+-- name: AttachMedia :execrows
+-- Attaches the user's unattached uploads to a chirp in the order given. The
+-- caller checks every one was attached.
+UPDATE media
+SET chirp_id = sqlc.arg('chirp_id'),
+    attached_at = NOW(),
+    position = array_position(sqlc.arg('ids')::uuid[], id) - 1
+WHERE id = ANY(sqlc.arg('ids')::uuid[])
+AND user_id = sqlc.arg('user_id')
+AND attached_at IS NULL;
+
+-- name: ListChirpMedia :many
+SELECT * FROM media
+WHERE chirp_id = ANY(sqlc.arg('chirp_ids')::uuid[])
+ORDER BY chirp_id, position;
+
+-- name: ListDetachedMedia :many
+-- Uploads whose chirp was deleted, and ones never attached to a chirp
+-- before the cutoff
+SELECT * FROM media
+WHERE chirp_id IS NULL
+AND (attached_at IS NOT NULL OR created_at < sqlc.arg('cutoff'))
+ORDER BY created_at
+LIMIT sqlc.arg('row_limit');
+
+-- name: DeleteMedia :exec
+DELETE FROM media
+WHERE id = $1;
@@ -0,0 +1,19 @@
+-- name: AddChirpMentions :exec
+INSERT INTO chirp_mentions (chirp_id, user_id, start_index, end_index)
+SELECT sqlc.arg('chirp_id'), mentions.user_id, mentions.start_index, mentions.end_index
+FROM unnest(
+    sqlc.arg('user_ids')::uuid[],
+    sqlc.arg('start_indexes')::integer[],
+    sqlc.arg('end_indexes')::integer[]
+) AS mentions(user_id, start_index, end_index);
+
+-- name: DeleteChirpMentions :exec
+DELETE FROM chirp_mentions
+WHERE chirp_id = $1;
+
+-- name: ListChirpMentions :many
+SELECT chirp_mentions.chirp_id, chirp_mentions.user_id, chirp_mentions.start_index, chirp_mentions.end_index, users.username
+FROM chirp_mentions
+JOIN users ON chirp_mentions.user_id = users.id
+WHERE chirp_mentions.chirp_id = ANY(sqlc.arg('chirp_ids')::uuid[])
+ORDER BY chirp_mentions.chirp_id, chirp_mentions.start_index;
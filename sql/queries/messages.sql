@@ -0,0 +1,53 @@
+-- name: CreateMessage :one
+-- Also bumps the conversation to the top of everyone's list and marks the
+-- message read for its sender
+WITH new_message AS (
+    INSERT INTO messages (id, created_at, conversation_id, sender_id, body)
+    VALUES (
+        gen_random_uuid(),
+        NOW(),
+        sqlc.arg('conversation_id'),
+        sqlc.arg('sender_id'),
+        sqlc.arg('body')
+    )
+    RETURNING *
+), touched AS (
+    UPDATE conversations SET updated_at = NOW()
+    WHERE id = sqlc.arg('conversation_id')
+), sender_read AS (
+    UPDATE conversation_participants
+    SET last_read_message_id = new_message.id, last_read_at = new_message.created_at
+    FROM new_message
+    WHERE conversation_participants.conversation_id = new_message.conversation_id
+    AND conversation_participants.user_id = new_message.sender_id
+)
+SELECT * FROM new_message;
+
+-- name: GetMessage :one
+SELECT * FROM messages
+WHERE id = $1;
+
+-- name: ListLatestMessages :many
+SELECT DISTINCT ON (conversation_id) * FROM messages
+WHERE conversation_id = ANY(sqlc.arg('conversation_ids')::uuid[])
+ORDER BY conversation_id, created_at DESC, id DESC;
+
+-- name: ListMessagesAsc :many
+SELECT * FROM messages
+WHERE conversation_id = sqlc.arg('conversation_id')
+AND (
+    sqlc.narg('cursor_created_at')::timestamp IS NULL
+    OR (created_at, id) > (sqlc.narg('cursor_created_at')::timestamp, sqlc.narg('cursor_id')::uuid)
+)
+ORDER BY created_at ASC, id ASC
+LIMIT sqlc.arg('row_limit');
+
+-- name: ListMessagesDesc :many
+SELECT * FROM messages
+WHERE conversation_id = sqlc.arg('conversation_id')
+AND (
+    sqlc.narg('cursor_created_at')::timestamp IS NULL
+    OR (created_at, id) < (sqlc.narg('cursor_created_at')::timestamp, sqlc.narg('cursor_id')::uuid)
+)
+ORDER BY created_at DESC, id DESC
+LIMIT sqlc.arg('row_limit');
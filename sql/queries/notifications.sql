@@ -0,0 +1,56 @@
+-- name: CreateNotification :one
+-- Returns no rows when the user has blocked or muted the actor
+INSERT INTO notifications (id, created_at, user_id, actor_id, kind, chirp_id)
+SELECT
+    gen_random_uuid(),
+    NOW(),
+    sqlc.arg('user_id')::uuid,
+    sqlc.narg('actor_id')::uuid,
+    sqlc.arg('kind')::text,
+    sqlc.narg('chirp_id')::uuid
+WHERE NOT EXISTS (
+    SELECT 1 FROM hidden_users
+    WHERE user_id = sqlc.arg('user_id') AND hidden_user_id = sqlc.narg('actor_id')
+)
+RETURNING *;
+
+-- name: GetNotification :one
+SELECT * FROM notifications
+WHERE id = $1;
+
+-- name: ListNotificationsAsc :many
+SELECT * FROM notifications
+WHERE user_id = sqlc.arg('user_id')
+AND (NOT sqlc.arg('unread_only')::boolean OR read_at IS NULL)
+AND (
+    sqlc.narg('cursor_created_at')::timestamp IS NULL
+    OR (created_at, id) > (sqlc.narg('cursor_created_at')::timestamp, sqlc.narg('cursor_id')::uuid)
+)
+ORDER BY created_at ASC, id ASC
+LIMIT sqlc.arg('row_limit');
+
+-- name: ListNotificationsDesc :many
+SELECT * FROM notifications
+WHERE user_id = sqlc.arg('user_id')
+AND (NOT sqlc.arg('unread_only')::boolean OR read_at IS NULL)
+AND (
+    sqlc.narg('cursor_created_at')::timestamp IS NULL
+    OR (created_at, id) < (sqlc.narg('cursor_created_at')::timestamp, sqlc.narg('cursor_id')::uuid)
+)
+ORDER BY created_at DESC, id DESC
+LIMIT sqlc.arg('row_limit');
+
+-- name: CountUnreadNotifications :one
+SELECT COUNT(*) FROM notifications
+WHERE user_id = $1 AND read_at IS NULL;
+
+-- name: MarkNotificationsRead :exec
+-- Marks everything up to and including the given position as read, or
+-- everything if there's no position
+UPDATE notifications SET read_at = NOW()
+WHERE user_id = sqlc.arg('user_id')
+AND read_at IS NULL
+AND (
+    sqlc.narg('through_created_at')::timestamp IS NULL
+    OR (created_at, id) <= (sqlc.narg('through_created_at')::timestamp, sqlc.narg('through_id')::uuid)
+);
@@ -0,0 +1,66 @@
+-- name: CreatePoll :exec
+INSERT INTO polls (chirp_id, created_at, closes_at)
+VALUES ($1, NOW(), $2);
+
+-- name: AddPollOptions :exec
+INSERT INTO poll_options (chirp_id, position, text)
+SELECT sqlc.arg('chirp_id'), options.ordinality - 1, options.text
+FROM unnest(sqlc.arg('texts')::text[]) WITH ORDINALITY AS options(text, ordinality);
+
+-- name: GetPoll :one
+SELECT * FROM polls
+WHERE chirp_id = $1;
+
+-- name: ListPolls :many
+SELECT * FROM polls
+WHERE chirp_id = ANY(sqlc.arg('chirp_ids')::uuid[]);
+
+-- name: ListPollOptions :many
+-- Options with how many votes each has
+SELECT
+    poll_options.chirp_id,
+    poll_options.position,
+    poll_options.text,
+    COUNT(poll_votes.user_id) AS vote_count
+FROM poll_options
+LEFT JOIN poll_votes ON poll_votes.chirp_id = poll_options.chirp_id
+    AND poll_votes.position = poll_options.position
+WHERE poll_options.chirp_id = ANY(sqlc.arg('chirp_ids')::uuid[])
+GROUP BY poll_options.chirp_id, poll_options.position, poll_options.text
+ORDER BY poll_options.chirp_id, poll_options.position;
+
+-- name: ListPollVotesByUser :many
+SELECT * FROM poll_votes
+WHERE user_id = sqlc.arg('user_id')
+AND chirp_id = ANY(sqlc.arg('chirp_ids')::uuid[]);
+
+-- name: CastPollVote :execrows
+-- Saves or changes the user's vote, as long as the poll is still open. No
+-- rows means it has closed.
+INSERT INTO poll_votes (chirp_id, user_id, position, created_at, updated_at)
+SELECT polls.chirp_id, sqlc.arg('user_id'), sqlc.arg('position'), NOW(), NOW()
+FROM polls
+WHERE polls.chirp_id = sqlc.arg('chirp_id')
+AND polls.closes_at > NOW()
+ON CONFLICT (chirp_id, user_id) DO UPDATE
+SET position = EXCLUDED.position, updated_at = NOW();
+
+-- name: ClaimClosedPolls :many
+-- Marks polls that have closed as notified and returns them with their
+-- authors. Instances running this at once each get different polls.
+WITH closed AS (
+    UPDATE polls
+    SET close_notified_at = NOW()
+    WHERE polls.chirp_id IN (
+        SELECT unnotified.chirp_id FROM polls AS unnotified
+        WHERE unnotified.closes_at <= NOW()
+        AND unnotified.close_notified_at IS NULL
+        ORDER BY unnotified.closes_at
+        LIMIT sqlc.arg('row_limit')
+        FOR UPDATE SKIP LOCKED
+    )
+    RETURNING polls.chirp_id
+)
+SELECT chirps.id, chirps.user_id
+FROM closed
+JOIN chirps ON chirps.id = closed.chirp_id;
@@ -1,23 +1,31 @@
 -- name: CreateRefreshToken :one
-INSERT INTO refresh_tokens (token, created_at, updated_at, user_id, expires_at)
+INSERT INTO refresh_tokens (token, created_at, updated_at, user_id, expires_at, session_id)
 VALUES (
     $1,
     NOW(),
     NOW(),
     $2,
-    $3
+    $3,
+    $4
 )
 RETURNING *;
 
--- name: RevokeRefreshToken :one
-UPDATE refresh_tokens SET revoked_at = NOW(),
+-- name: GetRefreshToken :one
+SELECT * FROM refresh_tokens
+WHERE token = $1;
+
+-- name: UseRefreshToken :one
+-- Marks a live token used. Of two requests using the same token at once,
+-- only one gets a row back.
+UPDATE refresh_tokens SET used_at = NOW(),
 updated_at = NOW()
 WHERE token = $1
-RETURNING *;
-
--- name: GetUserFromRefreshToken :one
-SELECT users.* FROM users
-JOIN refresh_tokens ON users.id = refresh_tokens.user_id
-WHERE refresh_tokens.token = $1
+AND used_at IS NULL
 AND revoked_at IS NULL
-AND expires_at > NOW();
+AND expires_at > NOW()
+AND EXISTS (
+    SELECT 1 FROM sessions
+    WHERE sessions.id = refresh_tokens.session_id
+    AND sessions.revoked_at IS NULL
+)
+RETURNING *;
@@ -0,0 +1,80 @@
+-- name: CreateReport :one
+INSERT INTO reports (id, created_at, reporter_id, target_kind, chirp_id, user_id, reason, details)
+VALUES (
+    gen_random_uuid(),
+    NOW(),
+    $1,
+    $2,
+    $3,
+    $4,
+    $5,
+    $6
+)
+RETURNING *;
+
+-- name: GetReport :one
+SELECT * FROM reports
+WHERE id = $1;
+
+-- name: ListReportsAsc :many
+SELECT * FROM reports
+WHERE (decision_id IS NOT NULL) = sqlc.arg('resolved')::boolean
+AND (
+    sqlc.narg('cursor_created_at')::timestamp IS NULL
+    OR (created_at, id) > (sqlc.narg('cursor_created_at')::timestamp, sqlc.narg('cursor_id')::uuid)
+)
+ORDER BY created_at ASC, id ASC
+LIMIT sqlc.arg('row_limit');
+
+-- name: ListReportsDesc :many
+SELECT * FROM reports
+WHERE (decision_id IS NOT NULL) = sqlc.arg('resolved')::boolean
+AND (
+    sqlc.narg('cursor_created_at')::timestamp IS NULL
+    OR (created_at, id) < (sqlc.narg('cursor_created_at')::timestamp, sqlc.narg('cursor_id')::uuid)
+)
+ORDER BY created_at DESC, id DESC
+LIMIT sqlc.arg('row_limit');
+
+-- name: ResolveReports :execrows
+-- A decision closes every open report about the same chirp or user
+UPDATE reports SET decision_id = sqlc.arg('decision_id')
+WHERE decision_id IS NULL
+AND target_kind = sqlc.arg('target_kind')
+AND (
+    (target_kind = 'chirp' AND chirp_id = sqlc.narg('chirp_id'))
+    OR (target_kind = 'user' AND user_id = sqlc.arg('user_id'))
+);
+
+-- name: CreateModerationDecision :one
+INSERT INTO moderation_decisions (id, created_at, moderator_id, report_id, target_kind, chirp_id, user_id, action, note)
+VALUES (
+    gen_random_uuid(),
+    NOW(),
+    $1,
+    $2,
+    $3,
+    $4,
+    $5,
+    $6,
+    $7
+)
+RETURNING *;
+
+-- name: ListModerationDecisionsAsc :many
+SELECT * FROM moderation_decisions
+WHERE (
+    sqlc.narg('cursor_created_at')::timestamp IS NULL
+    OR (created_at, id) > (sqlc.narg('cursor_created_at')::timestamp, sqlc.narg('cursor_id')::uuid)
+)
+ORDER BY created_at ASC, id ASC
+LIMIT sqlc.arg('row_limit');
+
+-- name: ListModerationDecisionsDesc :many
+SELECT * FROM moderation_decisions
+WHERE (
+    sqlc.narg('cursor_created_at')::timestamp IS NULL
+    OR (created_at, id) < (sqlc.narg('cursor_created_at')::timestamp, sqlc.narg('cursor_id')::uuid)
+)
+ORDER BY created_at DESC, id DESC
+LIMIT sqlc.arg('row_limit');
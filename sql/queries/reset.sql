@@ -1,2 +1,4 @@
 -- name: Reset :exec
-DELETE FROM users;
+-- Admins are kept so someone can still reset the server afterwards
+DELETE FROM users
+WHERE role <> 'admin';
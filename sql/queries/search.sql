@@ -0,0 +1,65 @@
+-- name: SearchChirpsAsc :many
+WITH query AS (
+    SELECT websearch_to_tsquery('english', sqlc.arg('query')) AS q
+), page AS (
+    SELECT chirps.*, ts_rank(chirps.search_vector, query.q)::real AS rank
+    FROM chirps, query
+    WHERE chirps.search_vector @@ query.q
+    AND (sqlc.narg('author_id')::uuid IS NULL OR chirps.user_id = sqlc.narg('author_id'))
+    AND (sqlc.narg('since')::timestamp IS NULL OR chirps.created_at >= sqlc.narg('since'))
+    AND (sqlc.narg('until')::timestamp IS NULL OR chirps.created_at < sqlc.narg('until'))
+    AND chirps.hidden_at IS NULL
+    AND NOT EXISTS (
+        SELECT 1 FROM hidden_users
+        WHERE hidden_users.user_id = sqlc.narg('viewer_id') AND hidden_users.hidden_user_id = chirps.user_id
+    )
+    AND (
+        sqlc.narg('cursor_rank')::real IS NULL
+        OR (ts_rank(chirps.search_vector, query.q)::real, chirps.created_at, chirps.id)
+        > (sqlc.narg('cursor_rank')::real, sqlc.narg('cursor_created_at')::timestamp, sqlc.narg('cursor_id')::uuid)
+    )
+    ORDER BY rank ASC, chirps.created_at ASC, chirps.id ASC
+    LIMIT sqlc.arg('row_limit')
+)
+-- Bodies are HTML escaped before highlighting so snippets are safe to render
+SELECT page.*, ts_headline(
+    'english',
+    replace(replace(replace(page.body, '&', '&amp;'), '<', '&lt;'), '>', '&gt;'),
+    query.q,
+    'StartSel=<mark>, StopSel=</mark>, MaxFragments=2'
+) AS snippet
+FROM page, query
+ORDER BY page.rank ASC, page.created_at ASC, page.id ASC;
+
+-- name: SearchChirpsDesc :many
+WITH query AS (
+    SELECT websearch_to_tsquery('english', sqlc.arg('query')) AS q
+), page AS (
+    SELECT chirps.*, ts_rank(chirps.search_vector, query.q)::real AS rank
+    FROM chirps, query
+    WHERE chirps.search_vector @@ query.q
+    AND (sqlc.narg('author_id')::uuid IS NULL OR chirps.user_id = sqlc.narg('author_id'))
+    AND (sqlc.narg('since')::timestamp IS NULL OR chirps.created_at >= sqlc.narg('since'))
+    AND (sqlc.narg('until')::timestamp IS NULL OR chirps.created_at < sqlc.narg('until'))
+    AND chirps.hidden_at IS NULL
+    AND NOT EXISTS (
+        SELECT 1 FROM hidden_users
+        WHERE hidden_users.user_id = sqlc.narg('viewer_id') AND hidden_users.hidden_user_id = chirps.user_id
+    )
+    AND (
+        sqlc.narg('cursor_rank')::real IS NULL
+        OR (ts_rank(chirps.search_vector, query.q)::real, chirps.created_at, chirps.id)
+        < (sqlc.narg('cursor_rank')::real, sqlc.narg('cursor_created_at')::timestamp, sqlc.narg('cursor_id')::uuid)
+    )
+    ORDER BY rank DESC, chirps.created_at DESC, chirps.id DESC
+    LIMIT sqlc.arg('row_limit')
+)
+-- Bodies are HTML escaped before highlighting so snippets are safe to render
+SELECT page.*, ts_headline(
+    'english',
+    replace(replace(replace(page.body, '&', '&amp;'), '<', '&lt;'), '>', '&gt;'),
+    query.q,
+    'StartSel=<mark>, StopSel=</mark>, MaxFragments=2'
+) AS snippet
+FROM page, query
+ORDER BY page.rank DESC, page.created_at DESC, page.id DESC;
@@ -0,0 +1,73 @@
+-- name: CreateSession :one
+INSERT INTO sessions (id, created_at, user_id, user_agent, ip_address, last_used_at, expires_at)
+VALUES (
+    gen_random_uuid(),
+    NOW(),
+    $1,
+    $2,
+    $3,
+    NOW(),
+    $4
+)
+RETURNING *;
+
+-- name: TouchSession :exec
+UPDATE sessions SET last_used_at = NOW(),
+user_agent = $2,
+ip_address = $3,
+expires_at = $4
+WHERE id = $1;
+
+-- name: IsSessionActive :one
+SELECT EXISTS (
+    SELECT 1 FROM sessions
+    WHERE id = $1
+    AND user_id = $2
+    AND revoked_at IS NULL
+);
+
+-- name: ListSessionsAsc :many
+SELECT * FROM sessions
+WHERE user_id = sqlc.arg('user_id')
+AND revoked_at IS NULL
+AND expires_at > NOW()
+AND (
+    sqlc.narg('cursor_created_at')::timestamp IS NULL
+    OR (created_at, id) > (sqlc.narg('cursor_created_at')::timestamp, sqlc.narg('cursor_id')::uuid)
+)
+ORDER BY created_at ASC, id ASC
+LIMIT sqlc.arg('row_limit');
+
+-- name: ListSessionsDesc :many
+SELECT * FROM sessions
+WHERE user_id = sqlc.arg('user_id')
+AND revoked_at IS NULL
+AND expires_at > NOW()
+AND (
+    sqlc.narg('cursor_created_at')::timestamp IS NULL
+    OR (created_at, id) < (sqlc.narg('cursor_created_at')::timestamp, sqlc.narg('cursor_id')::uuid)
+)
+ORDER BY created_at DESC, id DESC
+LIMIT sqlc.arg('row_limit');
+
+-- name: RevokeSession :execrows
+UPDATE sessions SET revoked_at = NOW()
+WHERE id = $1
+AND user_id = $2
+AND revoked_at IS NULL;
+
+-- name: RevokeOtherSessions :execrows
+UPDATE sessions SET revoked_at = NOW()
+WHERE user_id = $1
+AND id <> $2
+AND revoked_at IS NULL;
+
+-- name: RevokeSessionByRefreshToken :execrows
+-- Revokes the session a refresh token belongs to, whether or not the token
+-- has been used
+UPDATE sessions SET revoked_at = NOW()
+WHERE id = (
+    SELECT session_id FROM refresh_tokens
+    WHERE refresh_tokens.token = $1
+)
+AND revoked_at IS NULL;
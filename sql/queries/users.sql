@@ -1,25 +1,77 @@
 -- name: CreateUser :one
-INSERT INTO users (id, created_at, updated_at, email, hashed_password)
+INSERT INTO users (id, created_at, updated_at, email, hashed_password, username)
 VALUES (
     gen_random_uuid(),
     NOW(),
     NOW(),
     $1,
-    $2
+    $2,
+    $3
 )
 RETURNING *;
 
+-- name: GetUser :one
+SELECT * FROM users
+WHERE id = $1;
+
 -- name: GetUserByEmail :one
 SELECT * FROM users
 WHERE email = $1;
 
+-- name: GetUsersByUsernames :many
+SELECT * FROM users
+WHERE LOWER(username) = ANY(sqlc.arg('usernames')::text[]);
+
 -- name: UpdateUser :one
-UPDATE users SET email = $2, hashed_password = $3, updated_at = NOW()
-WHERE id = $1
+UPDATE users SET email = sqlc.arg('email'), hashed_password = sqlc.arg('hashed_password'), username = COALESCE(sqlc.narg('username'), username), dm_policy = COALESCE(sqlc.narg('dm_policy'), dm_policy), updated_at = NOW()
+WHERE id = sqlc.arg('id')
 RETURNING *;
 
+-- name: UpdateUserPasswordHash :execrows
+-- Swaps in a rehashed password, unless the password changed in the meantime
+UPDATE users SET hashed_password = sqlc.arg('new_hash')
+WHERE id = sqlc.arg('id')
+AND hashed_password = sqlc.arg('old_hash');
+
 -- name: UpgradeToChirpyRed :one
 UPDATE users 
 SET is_chirpy_red = true, updated_at = NOW()
 WHERE id = $1
 RETURNING *;
+
+-- name: SetUserRole :one
+UPDATE users
+SET role = $2, updated_at = NOW()
+WHERE id = $1
+RETURNING *;
+
+-- name: BootstrapAdmin :execrows
+-- Makes the user with this email an admin, but only while there are no admins
+UPDATE users
+SET role = 'admin', updated_at = NOW()
+WHERE email = $1
+AND NOT EXISTS (
+    SELECT 1 FROM users WHERE role = 'admin'
+);
+
+-- name: ListUsersWithRolesAsc :many
+-- Everyone with a role above user
+SELECT * FROM users
+WHERE role != 'user'
+AND (
+    sqlc.narg('cursor_created_at')::timestamp IS NULL
+    OR (created_at, id) > (sqlc.narg('cursor_created_at')::timestamp, sqlc.narg('cursor_id')::uuid)
+)
+ORDER BY created_at ASC, id ASC
+LIMIT sqlc.arg('row_limit');
+
+-- name: ListUsersWithRolesDesc :many
+-- Everyone with a role above user
+SELECT * FROM users
+WHERE role != 'user'
+AND (
+    sqlc.narg('cursor_created_at')::timestamp IS NULL
+    OR (created_at, id) < (sqlc.narg('cursor_created_at')::timestamp, sqlc.narg('cursor_id')::uuid)
+)
+ORDER BY created_at DESC, id DESC
+LIMIT sqlc.arg('row_limit');
@@ -0,0 +1,25 @@
+-- +goose Up
+-- ancestor_ids lists every chirp above this one in its thread, root first.
+-- Neither it nor in_reply_to is a foreign key: when a chirp is deleted its
+-- replies keep their place in the thread and show a tombstone instead.
+ALTER TABLE chirps
+ADD COLUMN in_reply_to UUID,
+ADD COLUMN thread_id UUID,
+ADD COLUMN ancestor_ids UUID[] NOT NULL DEFAULT '{}';
+
+UPDATE chirps SET thread_id = id;
+
+ALTER TABLE chirps
+ALTER COLUMN thread_id SET NOT NULL;
+
+CREATE INDEX chirps_thread_id_idx ON chirps (thread_id);
+CREATE INDEX chirps_ancestor_ids_idx ON chirps USING GIN (ancestor_ids);
+
+-- +goose Down
+DROP INDEX chirps_ancestor_ids_idx;
+DROP INDEX chirps_thread_id_idx;
+
+ALTER TABLE chirps
+DROP COLUMN ancestor_ids,
+DROP COLUMN thread_id,
+DROP COLUMN in_reply_to;
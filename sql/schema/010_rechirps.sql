@@ -0,0 +1,17 @@
+-- +goose Up
+-- A rechirp is a chirp that points at another one. Plain rechirps have an
+-- empty body, quotes carry the quoting user's own text. Like in_reply_to,
+-- rechirp_of isn't a foreign key so deleting the original leaves a tombstone.
+ALTER TABLE chirps
+ADD COLUMN rechirp_of UUID;
+
+CREATE INDEX chirps_rechirp_of_idx ON chirps (rechirp_of);
+CREATE UNIQUE INDEX chirps_plain_rechirp_idx ON chirps (user_id, rechirp_of)
+WHERE rechirp_of IS NOT NULL AND body = '';
+
+-- +goose Down
+DROP INDEX chirps_plain_rechirp_idx;
+DROP INDEX chirps_rechirp_of_idx;
+
+ALTER TABLE chirps
+DROP COLUMN rechirp_of;
@@ -0,0 +1,32 @@
+-- +goose Up
+CREATE TABLE hashtags (
+    tag TEXT PRIMARY KEY,
+    created_at TIMESTAMP NOT NULL
+);
+
+-- created_at copies the chirp's so hashtag pages and trending windows
+-- don't need to join chirps
+CREATE TABLE chirp_hashtags (
+    chirp_id UUID NOT NULL REFERENCES chirps(id) ON DELETE CASCADE,
+    tag TEXT NOT NULL REFERENCES hashtags(tag) ON DELETE CASCADE,
+    created_at TIMESTAMP NOT NULL,
+    PRIMARY KEY (chirp_id, tag)
+);
+
+CREATE INDEX chirp_hashtags_tag_created_at_idx ON chirp_hashtags (tag, created_at, chirp_id);
+CREATE INDEX chirp_hashtags_created_at_idx ON chirp_hashtags (created_at);
+
+-- Each refresh of trending topics writes a whole new snapshot, readers only
+-- look at the latest one
+CREATE TABLE trending_hashtags (
+    tag TEXT NOT NULL REFERENCES hashtags(tag) ON DELETE CASCADE,
+    computed_at TIMESTAMP NOT NULL,
+    score DOUBLE PRECISION NOT NULL,
+    chirp_count INTEGER NOT NULL,
+    PRIMARY KEY (computed_at, tag)
+);
+
+-- +goose Down
+DROP TABLE trending_hashtags;
+DROP TABLE chirp_hashtags;
+DROP TABLE hashtags;
@@ -0,0 +1,33 @@
+-- +goose Up
+-- Usernames are optional so existing accounts keep working, but unique
+-- ignoring case. They're stored as the user typed them.
+ALTER TABLE users ADD COLUMN username TEXT;
+CREATE UNIQUE INDEX users_username_idx ON users (LOWER(username));
+
+-- Offsets are in characters and cover the whole "@username", end exclusive
+CREATE TABLE chirp_mentions (
+    chirp_id UUID NOT NULL REFERENCES chirps(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    start_index INTEGER NOT NULL,
+    end_index INTEGER NOT NULL,
+    PRIMARY KEY (chirp_id, start_index)
+);
+
+CREATE INDEX chirp_mentions_user_id_idx ON chirp_mentions (user_id);
+
+CREATE TABLE notifications (
+    id UUID PRIMARY KEY,
+    created_at TIMESTAMP NOT NULL,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    actor_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    kind TEXT NOT NULL,
+    chirp_id UUID REFERENCES chirps(id) ON DELETE CASCADE
+);
+
+CREATE INDEX notifications_user_id_created_at_idx ON notifications (user_id, created_at, id);
+
+-- +goose Down
+DROP TABLE notifications;
+DROP TABLE chirp_mentions;
+DROP INDEX users_username_idx;
+ALTER TABLE users DROP COLUMN username;
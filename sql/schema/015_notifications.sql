@@ -0,0 +1,12 @@
+-- +goose Up
+-- Some notifications, like a Chirpy Red upgrade, aren't caused by another user
+ALTER TABLE notifications ALTER COLUMN actor_id DROP NOT NULL;
+ALTER TABLE notifications ADD COLUMN read_at TIMESTAMP;
+
+CREATE INDEX notifications_unread_idx ON notifications (user_id) WHERE read_at IS NULL;
+
+-- +goose Down
+DROP INDEX notifications_unread_idx;
+ALTER TABLE notifications DROP COLUMN read_at;
+DELETE FROM notifications WHERE actor_id IS NULL;
+ALTER TABLE notifications ALTER COLUMN actor_id SET NOT NULL;
@@ -0,0 +1,17 @@
+-- +goose Up
+-- A short log of chirps being created and deleted. Every instance reads it to
+-- feed its live streams, and clients resume from it after reconnecting.
+-- chirp_id isn't a foreign key so deletions can be logged.
+CREATE TABLE chirp_events (
+    id BIGSERIAL PRIMARY KEY,
+    created_at TIMESTAMP NOT NULL,
+    kind TEXT NOT NULL,
+    chirp_id UUID NOT NULL,
+    user_id UUID NOT NULL,
+    tags TEXT[] NOT NULL
+);
+
+CREATE INDEX chirp_events_created_at_idx ON chirp_events (created_at);
+
+-- +goose Down
+DROP TABLE chirp_events;
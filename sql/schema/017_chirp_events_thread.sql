@@ -0,0 +1,11 @@
+-- +goose Up
+-- Lets live subscribers follow a single thread
+ALTER TABLE chirp_events ADD COLUMN thread_id UUID;
+UPDATE chirp_events SET thread_id = COALESCE(
+    (SELECT chirps.thread_id FROM chirps WHERE chirps.id = chirp_events.chirp_id),
+    chirp_events.chirp_id
+);
+ALTER TABLE chirp_events ALTER COLUMN thread_id SET NOT NULL;
+
+-- +goose Down
+ALTER TABLE chirp_events DROP COLUMN thread_id;
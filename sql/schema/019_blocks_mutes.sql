@@ -0,0 +1,29 @@
+-- +goose Up
+CREATE TABLE blocks (
+    blocker_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    blocked_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    created_at TIMESTAMP NOT NULL,
+    PRIMARY KEY (blocker_id, blocked_id),
+    CHECK (blocker_id != blocked_id)
+);
+
+CREATE INDEX blocks_blocked_id_idx ON blocks (blocked_id);
+
+CREATE TABLE mutes (
+    muter_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    muted_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    created_at TIMESTAMP NOT NULL,
+    PRIMARY KEY (muter_id, muted_id),
+    CHECK (muter_id != muted_id)
+);
+
+-- Everyone whose chirps user_id doesn't want to see
+CREATE VIEW hidden_users AS
+SELECT blocker_id AS user_id, blocked_id AS hidden_user_id FROM blocks
+UNION ALL
+SELECT muter_id AS user_id, muted_id AS hidden_user_id FROM mutes;
+
+-- +goose Down
+DROP VIEW hidden_users;
+DROP TABLE mutes;
+DROP TABLE blocks;
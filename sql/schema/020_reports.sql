@@ -0,0 +1,52 @@
+-- +goose Up
+ALTER TABLE users ADD COLUMN is_moderator BOOLEAN NOT NULL DEFAULT false;
+
+-- Hidden chirps are kept but only their author and moderators can see them
+ALTER TABLE chirps ADD COLUMN hidden_at TIMESTAMP;
+
+-- Every call a moderator makes. Targets aren't foreign keys so the record
+-- outlives a deleted chirp.
+CREATE TABLE moderation_decisions (
+    id UUID PRIMARY KEY,
+    created_at TIMESTAMP NOT NULL,
+    moderator_id UUID REFERENCES users(id) ON DELETE SET NULL,
+    report_id UUID NOT NULL,
+    target_kind TEXT NOT NULL,
+    chirp_id UUID,
+    user_id UUID NOT NULL,
+    action TEXT NOT NULL CHECK (action IN ('hide', 'delete', 'dismiss', 'resolve')),
+    note TEXT NOT NULL
+);
+
+CREATE INDEX moderation_decisions_created_at_idx ON moderation_decisions (created_at, id);
+
+-- user_id is the reported user, or the author of the reported chirp. A
+-- report is open until a decision closes it.
+CREATE TABLE reports (
+    id UUID PRIMARY KEY,
+    created_at TIMESTAMP NOT NULL,
+    reporter_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    target_kind TEXT NOT NULL CHECK (target_kind IN ('chirp', 'user')),
+    chirp_id UUID,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    reason TEXT NOT NULL CHECK (reason IN ('spam', 'harassment', 'hate', 'violence', 'sexual', 'self_harm', 'impersonation', 'other')),
+    details TEXT NOT NULL,
+    decision_id UUID REFERENCES moderation_decisions(id),
+    CHECK ((target_kind = 'chirp') = (chirp_id IS NOT NULL))
+);
+
+-- One open report per reporter and target
+CREATE UNIQUE INDEX reports_open_chirp_idx ON reports (reporter_id, chirp_id)
+    WHERE decision_id IS NULL AND target_kind = 'chirp';
+CREATE UNIQUE INDEX reports_open_user_idx ON reports (reporter_id, user_id)
+    WHERE decision_id IS NULL AND target_kind = 'user';
+
+CREATE INDEX reports_created_at_idx ON reports (created_at, id);
+CREATE INDEX reports_chirp_id_idx ON reports (chirp_id);
+CREATE INDEX reports_user_id_idx ON reports (user_id);
+
+-- +goose Down
+DROP TABLE reports;
+DROP TABLE moderation_decisions;
+ALTER TABLE chirps DROP COLUMN hidden_at;
+ALTER TABLE users DROP COLUMN is_moderator;
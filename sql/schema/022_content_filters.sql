@@ -0,0 +1,37 @@
+-- +goose Up
+-- Word lists admins edit through the API. Lists can also come from a file.
+CREATE TABLE content_filters (
+    name TEXT PRIMARY KEY,
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL,
+    action TEXT NOT NULL CHECK (action IN ('mask', 'reject', 'flag'))
+);
+
+CREATE TABLE content_filter_words (
+    filter_name TEXT NOT NULL REFERENCES content_filters(name) ON DELETE CASCADE,
+    word TEXT NOT NULL,
+    PRIMARY KEY (filter_name, word)
+);
+
+-- Reports a filter files on its own have no reporter
+ALTER TABLE reports ALTER COLUMN reporter_id DROP NOT NULL;
+ALTER TABLE reports DROP CONSTRAINT reports_reason_check;
+ALTER TABLE reports ADD CONSTRAINT reports_reason_check
+    CHECK (reason IN ('spam', 'harassment', 'hate', 'violence', 'sexual', 'self_harm', 'impersonation', 'other', 'filter'));
+
+-- One open filter report per target
+CREATE UNIQUE INDEX reports_open_filter_chirp_idx ON reports (chirp_id)
+    WHERE decision_id IS NULL AND target_kind = 'chirp' AND reporter_id IS NULL;
+CREATE UNIQUE INDEX reports_open_filter_user_idx ON reports (user_id)
+    WHERE decision_id IS NULL AND target_kind = 'user' AND reporter_id IS NULL;
+
+-- +goose Down
+DROP INDEX reports_open_filter_user_idx;
+DROP INDEX reports_open_filter_chirp_idx;
+DELETE FROM reports WHERE reporter_id IS NULL;
+ALTER TABLE reports DROP CONSTRAINT reports_reason_check;
+ALTER TABLE reports ADD CONSTRAINT reports_reason_check
+    CHECK (reason IN ('spam', 'harassment', 'hate', 'violence', 'sexual', 'self_harm', 'impersonation', 'other'));
+ALTER TABLE reports ALTER COLUMN reporter_id SET NOT NULL;
+DROP TABLE content_filter_words;
+DROP TABLE content_filters;
@@ -0,0 +1,27 @@
+-- +goose Up
+-- Images are uploaded first and attached to a chirp when it's posted.
+-- Files live in storage under storage_key and thumbnail_key.
+CREATE TABLE media (
+    id UUID PRIMARY KEY,
+    created_at TIMESTAMP NOT NULL,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    -- Set to NULL when the chirp is deleted, so the files can be cleaned up
+    chirp_id UUID REFERENCES chirps(id) ON DELETE SET NULL,
+    attached_at TIMESTAMP,
+    position INTEGER NOT NULL DEFAULT 0,
+    content_type TEXT NOT NULL,
+    storage_key TEXT NOT NULL,
+    width INTEGER NOT NULL,
+    height INTEGER NOT NULL,
+    size_bytes INTEGER NOT NULL,
+    thumbnail_key TEXT NOT NULL,
+    thumbnail_width INTEGER NOT NULL,
+    thumbnail_height INTEGER NOT NULL,
+    alt_text TEXT NOT NULL
+);
+
+CREATE INDEX media_chirp_id_idx ON media (chirp_id, position);
+CREATE INDEX media_detached_idx ON media (created_at) WHERE chirp_id IS NULL;
+
+-- +goose Down
+DROP TABLE media;
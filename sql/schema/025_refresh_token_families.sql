@@ -0,0 +1,15 @@
+-- +goose Up
+-- Every refresh hands out a new token in the same family and marks the old
+-- one used. A used token showing up again means it was copied, so the whole
+-- family is revoked.
+ALTER TABLE refresh_tokens ADD COLUMN family_id UUID;
+UPDATE refresh_tokens SET family_id = gen_random_uuid();
+ALTER TABLE refresh_tokens ALTER COLUMN family_id SET NOT NULL;
+ALTER TABLE refresh_tokens ADD COLUMN used_at TIMESTAMP;
+
+CREATE INDEX refresh_tokens_family_id_idx ON refresh_tokens (family_id);
+
+-- +goose Down
+DROP INDEX refresh_tokens_family_id_idx;
+ALTER TABLE refresh_tokens DROP COLUMN used_at;
+ALTER TABLE refresh_tokens DROP COLUMN family_id;
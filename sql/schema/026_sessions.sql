@@ -0,0 +1,33 @@
+-- +goose Up
+-- A session is one login on one device. Its refresh tokens are the token
+-- family from 025, so revoking the session revokes all of them.
+CREATE TABLE sessions (
+    id UUID PRIMARY KEY,
+    created_at TIMESTAMP NOT NULL,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    user_agent TEXT NOT NULL DEFAULT '',
+    ip_address TEXT NOT NULL DEFAULT '',
+    -- Last login or refresh
+    last_used_at TIMESTAMP NOT NULL,
+    -- When its newest refresh token expires
+    expires_at TIMESTAMP NOT NULL,
+    revoked_at TIMESTAMP
+);
+
+CREATE INDEX sessions_user_id_idx ON sessions (user_id, created_at);
+
+INSERT INTO sessions (id, created_at, user_id, last_used_at, expires_at, revoked_at)
+SELECT family_id, MIN(created_at), user_id, MAX(updated_at), MAX(expires_at), MAX(revoked_at)
+FROM refresh_tokens
+GROUP BY family_id, user_id;
+
+ALTER TABLE refresh_tokens RENAME COLUMN family_id TO session_id;
+ALTER INDEX refresh_tokens_family_id_idx RENAME TO refresh_tokens_session_id_idx;
+ALTER TABLE refresh_tokens ADD CONSTRAINT refresh_tokens_session_id_fkey
+    FOREIGN KEY (session_id) REFERENCES sessions(id) ON DELETE CASCADE;
+
+-- +goose Down
+ALTER TABLE refresh_tokens DROP CONSTRAINT refresh_tokens_session_id_fkey;
+ALTER INDEX refresh_tokens_session_id_idx RENAME TO refresh_tokens_family_id_idx;
+ALTER TABLE refresh_tokens RENAME COLUMN session_id TO family_id;
+DROP TABLE sessions;
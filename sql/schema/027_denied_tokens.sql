@@ -0,0 +1,13 @@
+-- +goose Up
+-- Access JWTs turned down before they expire, by jti. Rows are only needed
+-- until the token would have expired anyway.
+CREATE TABLE denied_tokens (
+    jti TEXT PRIMARY KEY,
+    created_at TIMESTAMP NOT NULL,
+    expires_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX denied_tokens_expires_at_idx ON denied_tokens (expires_at);
+
+-- +goose Down
+DROP TABLE denied_tokens;
@@ -0,0 +1,7 @@
+-- +goose Up
+-- 020 said authors could still see their hidden chirps. They can't, they're
+-- only told it was hidden, so say so where it'll be seen.
+COMMENT ON COLUMN chirps.hidden_at IS 'When a moderator hid the chirp. Only moderators can see hidden chirps; the author is told it was hidden.';
+
+-- +goose Down
+COMMENT ON COLUMN chirps.hidden_at IS NULL;
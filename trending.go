@@ -0,0 +1,54 @@
+package main
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/srinivassivaratri/Chirpy/internal/database"
+)
+
+const (
+	// How often trending topics are recomputed
+	trendingRefreshInterval = 5 * time.Minute
+	// Only hashtags used this recently can trend
+	trendingWindow = 24 * time.Hour
+	// A use of a hashtag counts half as much after this long
+	trendingHalfLife = 3 * time.Hour
+	// How many hashtags each snapshot keeps
+	trendingSnapshotSize = 50
+)
+
+// refreshTrendingLoop recomputes trending hashtags in the background so
+// GET /api/trending only has to read the latest snapshot. Every instance
+// runs one; snapshots are written in a single statement so readers never
+// see a half-written one.
+func (cfg *apiConfig) refreshTrendingLoop(ctx context.Context) {
+	ticker := time.NewTicker(trendingRefreshInterval)
+	defer ticker.Stop()
+	for {
+		err := cfg.refreshTrending(ctx)
+		if err != nil {
+			log.Printf("Error refreshing trending hashtags: %s", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (cfg *apiConfig) refreshTrending(ctx context.Context) error {
+	computedAt := time.Now().UTC()
+	err := cfg.db.CreateTrendingSnapshot(ctx, database.CreateTrendingSnapshotParams{
+		ComputedAt:      computedAt,
+		HalfLifeSeconds: trendingHalfLife.Seconds(),
+		WindowSeconds:   trendingWindow.Seconds(),
+		RowLimit:        trendingSnapshotSize,
+	})
+	if err != nil {
+		return err
+	}
+	return cfg.db.DeleteTrendingSnapshotsBefore(ctx, computedAt)
+}
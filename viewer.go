@@ -0,0 +1,45 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/srinivassivaratri/Chirpy/internal/auth"
+)
+
+// viewerID returns the user making the request on endpoints where logging in
+// is optional. Requests without a JWT are anonymous, but a JWT that doesn't
+// validate is still an error.
+func (cfg *apiConfig) viewerID(r *http.Request) (uuid.NullUUID, error) {
+	token, err := auth.GetBearerToken(r.Header)
+	if errors.Is(err, auth.ErrNoAuthHeaderIncluded) {
+		return uuid.NullUUID{}, nil
+	}
+	if err != nil {
+		return uuid.NullUUID{}, err
+	}
+	userID, err := cfg.validateJWT(r.Context(), token)
+	if err != nil {
+		return uuid.NullUUID{}, err
+	}
+	return uuid.NullUUID{UUID: userID, Valid: true}, nil
+}
+
+// hiddenUsers returns the users whose chirps the viewer has blocked or muted,
+// or nil for anonymous viewers
+func (cfg *apiConfig) hiddenUsers(ctx context.Context, viewerID uuid.NullUUID) (map[uuid.UUID]bool, error) {
+	if !viewerID.Valid {
+		return nil, nil
+	}
+	ids, err := cfg.db.ListHiddenUserIDs(ctx, viewerID.UUID)
+	if err != nil {
+		return nil, err
+	}
+	hidden := map[uuid.UUID]bool{}
+	for _, id := range ids {
+		hidden[id] = true
+	}
+	return hidden, nil
+}